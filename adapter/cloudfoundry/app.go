@@ -1,8 +1,12 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"path"
+	"sort"
 )
 
 // AppEntity
@@ -52,8 +56,16 @@ type AppInfo struct {
 
 // GetApps
 func (c *CloudController) GetApps() error {
+	return c.GetAppsContext(context.Background())
+}
+
+// GetAppsContext is like GetApps but uses the given context.
+func (c *CloudController) GetAppsContext(ctx context.Context) error {
 
-	appResources, err := c.GetResourceList("/v2/apps")
+	appResources, err := c.GetResourceListContext(ctx, "/v2/apps")
+	if err != nil {
+		return err
+	}
 
 	resultMap := make(map[string]*AppInfo)
 
@@ -79,3 +91,74 @@ func (c *CloudController) GetApps() error {
 	return err
 
 }
+
+// GetV3Apps - Loads all apps from the v3 API.
+func (c *CloudController) GetV3Apps() error {
+	return c.GetV3AppsContext(context.Background())
+}
+
+// GetV3AppsContext is like GetV3Apps but uses the given context.
+func (c *CloudController) GetV3AppsContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/apps", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3AppMap, func(a *v3.App) string { return a.GUID })
+	return err
+}
+
+// QueryV3AppsContext loads the apps matching the query, e.g. a
+// label_selector, and adds them to V3AppMap.
+func (c *CloudController) QueryV3AppsContext(ctx context.Context, query *v3.ListQuery) ([]*v3.App, error) {
+	return queryV3(ctx, c, "/v3/apps", query, &c.V3AppMap, func(a *v3.App) string { return a.GUID })
+}
+
+// SelectV3Apps returns the already loaded apps whose labels match
+// the label selector, sorted by name.
+func (c *CloudController) SelectV3Apps(selector string) ([]*v3.App, error) {
+
+	labelSelector, err := v3.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*v3.App
+	c.mapMutex.Lock()
+	if c.V3AppMap != nil {
+		for _, a := range *c.V3AppMap {
+			if labelSelector.Matches(a.Metadata.GetLabels()) {
+				result = append(result, a)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// FilterV3AppsByName returns the apps whose names match the glob pattern,
+// e.g. "payment-*". See path.Match for the syntax of the pattern.
+func FilterV3AppsByName(apps []*v3.App, pattern string) ([]*v3.App, error) {
+
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid app name pattern %q: %v", pattern, err)
+	}
+
+	var result []*v3.App
+	for _, a := range apps {
+		if ok, _ := path.Match(pattern, a.Name); ok {
+			result = append(result, a)
+		}
+	}
+
+	return result, nil
+}
+
+// V3Apps returns the loaded apps in no particular order.
+func (c *CloudController) V3Apps() []*v3.App {
+	return valuesV3(c, &c.V3AppMap)
+}
+
+// V3App returns the loaded app with the guid or nil.
+func (c *CloudController) V3App(guid string) *v3.App {
+	return lookupV3(c, &c.V3AppMap, guid)
+}
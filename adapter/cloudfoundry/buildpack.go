@@ -1,8 +1,10 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
 )
 
 // BuildpackEntity - Entity data for buildpacks.
@@ -23,8 +25,16 @@ type BuildpackInfo struct {
 
 // GetBuildpacks - Loads infos about all buildpacks
 func (c *CloudController) GetBuildpacks() error {
+	return c.GetBuildpacksContext(context.Background())
+}
+
+// GetBuildpacksContext is like GetBuildpacks but uses the given context.
+func (c *CloudController) GetBuildpacksContext(ctx context.Context) error {
 
-	buildpackResources, err := c.GetResourceList("/v2/buildpacks")
+	buildpackResources, err := c.GetResourceListContext(ctx, "/v2/buildpacks")
+	if err != nil {
+		return err
+	}
 
 	resultMap := make(map[string]*BuildpackInfo)
 
@@ -54,3 +64,20 @@ func (c *CloudController) GetBuildpacks() error {
 	return err
 
 }
+
+// GetV3Buildpacks - Loads all buildpacks from the v3 API.
+func (c *CloudController) GetV3Buildpacks() error {
+	return c.GetV3BuildpacksContext(context.Background())
+}
+
+// GetV3BuildpacksContext is like GetV3Buildpacks but uses the given context.
+func (c *CloudController) GetV3BuildpacksContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/buildpacks", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3BuildpackMap, func(b *v3.Buildpack) string { return b.GUID })
+	return err
+}
+
+// V3Buildpacks returns the loaded buildpacks in no particular order.
+func (c *CloudController) V3Buildpacks() []*v3.Buildpack {
+	return valuesV3(c, &c.V3BuildpackMap)
+}
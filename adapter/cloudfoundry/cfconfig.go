@@ -0,0 +1,156 @@
+package cloudfoundry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// CFConfig contains the parts of the cf CLI config file (~/.cf/config.json)
+// which are needed to reuse an existing cf CLI session.
+type CFConfig struct {
+	Target                string                   `json:"Target"`
+	AuthorizationEndpoint string                   `json:"AuthorizationEndpoint"`
+	UAAEndpoint           string                   `json:"UaaEndpoint"`
+	AccessToken           string                   `json:"AccessToken"`
+	RefreshToken          string                   `json:"RefreshToken"`
+	UAAOAuthClient        string                   `json:"UAAOAuthClient"`
+	UAAOAuthClientSecret  string                   `json:"UAAOAuthClientSecret"`
+	UAAGrantType          string                   `json:"UAAGrantType"`
+	SkipSSLValidation     bool                     `json:"SSLDisabled"`
+	OrganizationFields    CFConfigOrganizationInfo `json:"OrganizationFields"`
+	SpaceFields           CFConfigSpaceInfo        `json:"SpaceFields"`
+	// CACertPath is not part of the cf CLI config. It can be set to trust
+	// additional CA certificates, see CloudControllerConfig.CACertPath.
+	CACertPath string `json:"-"`
+}
+
+// CFConfigOrganizationInfo - The org currently targeted by the cf CLI.
+type CFConfigOrganizationInfo struct {
+	GUID string `json:"GUID"`
+	Name string `json:"Name"`
+}
+
+// CFConfigSpaceInfo - The space currently targeted by the cf CLI.
+type CFConfigSpaceInfo struct {
+	GUID string `json:"GUID"`
+	Name string `json:"Name"`
+}
+
+// DefaultCFConfigPath returns the location of the cf CLI config file. Like the
+// cf CLI it honours the CF_HOME environment variable.
+func DefaultCFConfigPath() (string, error) {
+
+	home := os.Getenv("CF_HOME")
+	if home == "" {
+		var err error
+		home, err = os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.Join(home, ".cf", "config.json"), nil
+}
+
+// LoadCFConfig reads the cf CLI config file at the given path. An empty path
+// means the default location.
+func LoadCFConfig(path string) (*CFConfig, error) {
+
+	if path == "" {
+		var err error
+		path, err = DefaultCFConfigPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfConfig CFConfig
+	err = json.Unmarshal(content, &cfConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfConfig.Target == "" {
+		return nil, errors.New("no api endpoint set in cf config, use 'cf api' first")
+	}
+
+	if cfConfig.AccessToken == "" {
+		return nil, errors.New("not logged in with the cf CLI, use 'cf login' first")
+	}
+
+	return &cfConfig, nil
+}
+
+// NewCloudControllerFromCFConfig returns a logged in CloudController which
+// reuses the session stored in the cf CLI config. An expired access token is
+// refreshed right away. The org and space targeted by the cf CLI become the
+// default scope for name resolution.
+func NewCloudControllerFromCFConfig(cfConfig *CFConfig) (*CloudController, error) {
+	return NewCloudControllerFromCFConfigContext(context.Background(), cfConfig)
+}
+
+// NewCloudControllerFromCFConfigContext is like NewCloudControllerFromCFConfig
+// but uses the given context to refresh the access token.
+func NewCloudControllerFromCFConfigContext(ctx context.Context, cfConfig *CFConfig) (*CloudController, error) {
+
+	if cfConfig == nil {
+		return nil, errors.New("cf config cannot be empty")
+	}
+
+	uaaEndpoint := cfConfig.UAAEndpoint
+	if uaaEndpoint == "" {
+		uaaEndpoint = cfConfig.AuthorizationEndpoint
+	}
+	if uaaEndpoint == "" {
+		return nil, errors.New("no UAA endpoint set in cf config, use 'cf login' first")
+	}
+
+	config := CloudControllerConfig{
+		AuthType:          AuthTypeAccessToken,
+		AccessToken:       cfConfig.AccessToken,
+		RefreshToken:      cfConfig.RefreshToken,
+		APIURLString:      cfConfig.Target,
+		SkipSSLValidation: cfConfig.SkipSSLValidation,
+		CACertPath:        cfConfig.CACertPath,
+	}
+
+	if cfConfig.UAAGrantType == "client_credentials" {
+		config.AuthType = AuthTypeClientCredentials
+		config.ClientID = cfConfig.UAAOAuthClient
+		config.ClientSecret = cfConfig.UAAOAuthClientSecret
+	}
+
+	c, err := NewCloudController(config)
+	if err != nil {
+		return nil, err
+	}
+
+	uaaURL, err := url.Parse(uaaEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	c.TokenURL = uaaURL.ResolveReference(&url.URL{Path: "/oauth/token"})
+
+	c.setAccessToken(cfConfig.AccessToken, cfConfig.RefreshToken)
+	c.TargetOrganizationName = cfConfig.OrganizationFields.Name
+	c.TargetSpaceName = cfConfig.SpaceFields.Name
+
+	if c.tokenExpired() {
+		err = c.RefreshAccessTokenContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
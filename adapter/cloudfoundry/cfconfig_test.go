@@ -0,0 +1,139 @@
+package cloudfoundry
+
+import (
+	"encoding/base64"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCFConfig(t *testing.T) {
+
+	testingJWT := func(expiresAt time.Time) string {
+		payload := fmt.Sprintf(`{"exp": %d}`, expiresAt.Unix())
+		return "eyhuetzli." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".gruetzli"
+	}
+
+	writeCFConfig := func(content string) string {
+		dir, _ := ioutil.TempDir("", "cfconfig")
+		path := filepath.Join(dir, "config.json")
+		ioutil.WriteFile(path, []byte(content), 0600)
+		return path
+	}
+
+	Convey("Given a cf config without access token", t, func() {
+
+		path := writeCFConfig(`{"Target": "https://api.mycloudcontroller"}`)
+		defer os.RemoveAll(filepath.Dir(path))
+
+		Convey("When the cf config is loaded", func() {
+
+			Convey("Then an error message indicates the missing cf login", func() {
+				_, err := LoadCFConfig(path)
+
+				So(err.Error(), ShouldEqual, "not logged in with the cf CLI, use 'cf login' first")
+			})
+
+		})
+
+	})
+
+	Convey("Given a cf config without UAA endpoint", t, func() {
+
+		path := writeCFConfig(`{
+			"Target": "https://api.mycloudcontroller",
+			"AccessToken": "bearer ` + testingJWT(time.Now().Add(time.Hour)) + `"
+		}`)
+		defer os.RemoveAll(filepath.Dir(path))
+
+		Convey("When a CloudController is created from the cf config", func() {
+
+			Convey("Then an error message indicates the missing UAA endpoint", func() {
+				cfConfig, err := LoadCFConfig(path)
+				So(err, ShouldEqual, nil)
+
+				cc, err := NewCloudControllerFromCFConfig(cfConfig)
+				So(cc, ShouldEqual, nil)
+				So(err.Error(), ShouldEqual, "no UAA endpoint set in cf config, use 'cf login' first")
+			})
+
+		})
+
+	})
+
+	Convey("Given a cf config with a valid session", t, func() {
+
+		path := writeCFConfig(`{
+			"Target": "https://api.mycloudcontroller",
+			"UaaEndpoint": "https://uaa.mycloudcontroller",
+			"AccessToken": "bearer ` + testingJWT(time.Now().Add(time.Hour)) + `",
+			"RefreshToken": "cfclirefreshtoken",
+			"SSLDisabled": true,
+			"OrganizationFields": {"GUID": "org-guid", "Name": "my-org"},
+			"SpaceFields": {"GUID": "space-guid", "Name": "my-space"}
+		}`)
+		defer os.RemoveAll(filepath.Dir(path))
+
+		Convey("When a CloudController is created from the cf config", func() {
+
+			Convey("Then the session of the cf CLI is reused", func() {
+				cfConfig, err := LoadCFConfig(path)
+				So(err, ShouldEqual, nil)
+				So(cfConfig.SkipSSLValidation, ShouldEqual, true)
+				So(cfConfig.OrganizationFields.Name, ShouldEqual, "my-org")
+				So(cfConfig.SpaceFields.GUID, ShouldEqual, "space-guid")
+
+				cc, err := NewCloudControllerFromCFConfig(cfConfig)
+				So(err, ShouldEqual, nil)
+				So(cc.APIUrl.Host, ShouldEqual, "api.mycloudcontroller")
+				So(cc.TokenURL.String(), ShouldEqual, "https://uaa.mycloudcontroller/oauth/token")
+				So(cc.AccessToken.RefreshToken, ShouldEqual, "cfclirefreshtoken")
+				So(cc.tokenExpired(), ShouldEqual, false)
+				So(cc.TargetOrganizationName, ShouldEqual, "my-org")
+				So(cc.TargetSpaceName, ShouldEqual, "my-space")
+			})
+
+		})
+
+	})
+
+	Convey("Given a cf config with an expired access token", t, func() {
+
+		Convey("When a CloudController is created from the cf config", func() {
+
+			Convey("Then the access token is refreshed", func(c C) {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					c.So(r.Method, ShouldEqual, "POST")
+					c.So(r.URL.Path, ShouldEqual, "/oauth/token")
+					w.Write([]byte(`{"access_token": "refreshedtoken", "refresh_token": "newrefreshtoken", "expires_in": 300}`))
+				})
+				uaa := httptest.NewServer(h)
+				defer uaa.Close()
+
+				path := writeCFConfig(`{
+					"Target": "http://api.mycloudcontroller",
+					"UaaEndpoint": "` + uaa.URL + `",
+					"AccessToken": "bearer ` + testingJWT(time.Now().Add(-time.Hour)) + `",
+					"RefreshToken": "cfclirefreshtoken"
+				}`)
+				defer os.RemoveAll(filepath.Dir(path))
+
+				cfConfig, err := LoadCFConfig(path)
+				So(err, ShouldEqual, nil)
+
+				cc, err := NewCloudControllerFromCFConfig(cfConfig)
+				So(err, ShouldEqual, nil)
+				So(cc.AccessToken.AccessToken, ShouldEqual, "refreshedtoken")
+			})
+
+		})
+
+	})
+
+}
@@ -1,35 +1,237 @@
 package cloudfoundry
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
 	"net/http"
 	"net/url"
-	"strings"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Supported authentication modes for CloudControllerConfig.AuthType.
+const (
+	// AuthTypePassword logs in with username and password (default).
+	AuthTypePassword = "password"
+	// AuthTypeClientCredentials logs in with a UAA client id and secret.
+	AuthTypeClientCredentials = "client_credentials"
+	// AuthTypePasscode logs in with a one-time passcode obtained via SSO.
+	AuthTypePasscode = "passcode"
+	// AuthTypeAccessToken uses a pre-issued bearer token without logging in.
+	AuthTypeAccessToken = "access_token"
 )
 
 // CloudControllerConfig provides Cloud Foundry specific configuration options
 type CloudControllerConfig struct {
+	AuthType     string
 	Username     string
 	Password     string
+	ClientID     string
+	ClientSecret string
+	Passcode     string
+	AccessToken  string
+	RefreshToken string
 	APIURLString string
 	APIURL       *url.URL
+	// SkipSSLValidation disables certificate verification like
+	// 'cf api --skip-ssl-validation'.
+	SkipSSLValidation bool
+	// CACertPath points to a PEM file or a directory of PEM files with
+	// additional CA certificates, e.g. for foundations with a private CA.
+	CACertPath string
+	// MaxRetries is the number of retries for transient failures. Zero means
+	// DefaultMaxRetries, a negative value disables retries.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry. It is doubled for each
+	// further retry up to MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// RequestsPerSecond caps the client side request rate. Zero means no cap.
+	RequestsPerSecond float64
+	// MaxConcurrency limits the number of pages of a v2 resource list or a
+	// v3 list which are fetched in parallel. Zero means
+	// DefaultMaxConcurrency.
+	MaxConcurrency int
 }
 
+// DefaultMaxConcurrency is used when CloudControllerConfig.MaxConcurrency is
+// not set.
+const DefaultMaxConcurrency = 4
+
 // CloudController provides access to the cc API.
 type CloudController struct {
 	Config             *CloudControllerConfig
 	APIUrl             *url.URL
 	httpClient         *http.Client
 	AccessToken        *AccessTokenInfo
+	TokenURL           *url.URL
+	tokenExpiresAt     time.Time
+	tokenMutex         sync.Mutex
+	rateLimiter        *rateLimiter
 	StackMap           *map[string]*StackInfo
 	BuildpackMap       *map[string]*BuildpackInfo
 	QuotaDefinitionMap *map[string]*QuotaDefinitionInfo
 	OrganizationMap    *map[string]*OrganizationInfo
 	SpaceMap           *map[string]*SpaceInfo
 	AppMap             *map[string]*AppInfo
+
+	V3OrganizationMap      *map[string]*v3.Organization
+	V3SpaceMap             *map[string]*v3.Space
+	V3StackMap             *map[string]*v3.Stack
+	V3BuildpackMap         *map[string]*v3.Buildpack
+	V3OrganizationQuotaMap *map[string]*v3.OrganizationQuota
+	V3SpaceQuotaMap        *map[string]*v3.SpaceQuota
+	V3AppMap               *map[string]*v3.App
+	V3RouteMap             *map[string]*v3.Route
+	V3DomainMap            *map[string]*v3.Domain
+
+	V3ServiceCredentialBindingMap *map[string]*v3.ServiceCredentialBinding
+	V3ServiceInstanceMap          *map[string]*v3.ServiceInstance
+	V3ServicePlanMap              *map[string]*v3.ServicePlan
+	V3ServiceOfferingMap          *map[string]*v3.ServiceOffering
+	V3ServiceBrokerMap            *map[string]*v3.ServiceBroker
+	V3ProcessMap                  *map[string]*v3.Process
+	V3ProcessStatsMap             *map[string][]*v3.ProcessInstanceStats
+	V3SidecarMap                  *map[string]*v3.Sidecar
+	V3TaskMap                     *map[string]*v3.Task
+	V3AppLineageMap               *map[string]*v3.AppLineage
+	V3SecurityGroupMap            *map[string]*v3.SecurityGroup
+	NetworkPolicyMap              *map[string]*NetworkPolicy
+	mapMutex                      sync.Mutex
+
+	// TargetOrganizationName and TargetSpaceName are the names of the org
+	// and space targeted by the cf CLI. They are the default scope for name
+	// resolution, see ResolveApp.
+	TargetOrganizationName string
+	TargetSpaceName        string
+
+	// trustedOrigins are the scheme://host of URLs besides the API URL
+	// which may receive the access token, e.g. the policy server.
+	trustedOrigins map[string]bool
+}
+
+// v3MaxPerPage is the largest page size accepted by the v3 API.
+const v3MaxPerPage = v3.MaxPerPage
+
+// v3MaxFilterValues limits the values of a single filter per request. Longer
+// filters are split over several requests, as very long URLs are rejected by
+// the cc API or the gorouter.
+const v3MaxFilterValues = 100
+
+// keys returns the keys of the set sorted ascending.
+func keys(set map[string]bool) []string {
+
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// queryV3 loads the resources of the v3 list at the path matching the query
+// and adds them to the map m by their guid.
+func queryV3[T any](ctx context.Context, c *CloudController, path string, query *v3.ListQuery, m **map[string]*T, guid func(*T) string) ([]*T, error) {
+
+	err := checkLabelSelector(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := listV3[T](ctx, c, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	storeV3(c, m, result, guid)
+
+	return result, nil
+}
+
+// listV3 loads and decodes the resources of the v3 list at the path matching
+// the query.
+func listV3[T any](ctx context.Context, c *CloudController, path string, query *v3.ListQuery) ([]*T, error) {
+
+	var result []*T
+	_, err := c.ListV3(ctx, path, query, func(resource json.RawMessage) error {
+		r := new(T)
+		err := json.Unmarshal(resource, r)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// storeV3 adds the resources to the map m by their guid. The map is created
+// if needed.
+func storeV3[T any](c *CloudController, m **map[string]*T, resources []*T, guid func(*T) string) {
+
+	c.mapMutex.Lock()
+	defer c.mapMutex.Unlock()
+
+	if *m == nil {
+		resultMap := make(map[string]*T)
+		*m = &resultMap
+	}
+	for _, r := range resources {
+		(**m)[guid(r)] = r
+	}
+}
+
+// valuesV3 returns the resources of the map m in no particular order.
+func valuesV3[T any](c *CloudController, m **map[string]*T) []*T {
+
+	c.mapMutex.Lock()
+	defer c.mapMutex.Unlock()
+
+	if *m == nil {
+		return nil
+	}
+
+	result := make([]*T, 0, len(**m))
+	for _, r := range **m {
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// lookupV3 returns the resource of the map m with the guid or nil.
+func lookupV3[T any](c *CloudController, m **map[string]*T, guid string) *T {
+
+	c.mapMutex.Lock()
+	defer c.mapMutex.Unlock()
+
+	if *m == nil {
+		return nil
+	}
+
+	return (**m)[guid]
+}
+
+// checkLabelSelector validates the label selector of the query before it is
+// sent to the cc API.
+func checkLabelSelector(query *v3.ListQuery) error {
+
+	if query == nil || query.LabelSelector == "" {
+		return nil
+	}
+
+	_, err := v3.ParseLabelSelector(query.LabelSelector)
+	return err
 }
 
 // NewCloudController returns a new CloudController client for the given url.
@@ -41,8 +243,13 @@ func NewCloudController(config CloudControllerConfig) (*CloudController, error)
 		return nil, err
 	}
 
+	tlsConfig, err := newTLSConfig(&config)
+	if err != nil {
+		return nil, err
+	}
+
 	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig: tlsConfig,
 		Proxy:           http.ProxyFromEnvironment,
 	}
 
@@ -50,6 +257,9 @@ func NewCloudController(config CloudControllerConfig) (*CloudController, error)
 
 	c := &CloudController{httpClient: httpClient, APIUrl: config.APIURL, Config: &config}
 
+	_, _, maxBackoff := c.retrySettings()
+	c.rateLimiter = newRateLimiter(config.RequestsPerSecond, maxBackoff)
+
 	return c, nil
 }
 
@@ -59,12 +269,33 @@ func checkConfig(c *CloudControllerConfig) error {
 		return errors.New("config cannot be empty")
 	}
 
-	if c.Username == "" {
-		return errors.New("username cannot be empty")
-	}
-
-	if c.Password == "" {
-		return errors.New("password cannot be empty")
+	switch c.AuthType {
+	case "", AuthTypePassword:
+		if c.Username == "" {
+			return errors.New("username cannot be empty")
+		}
+
+		if c.Password == "" {
+			return errors.New("password cannot be empty")
+		}
+	case AuthTypeClientCredentials:
+		if c.ClientID == "" {
+			return errors.New("clientId cannot be empty")
+		}
+
+		if c.ClientSecret == "" {
+			return errors.New("clientSecret cannot be empty")
+		}
+	case AuthTypePasscode:
+		if c.Passcode == "" {
+			return errors.New("passcode cannot be empty")
+		}
+	case AuthTypeAccessToken:
+		if c.AccessToken == "" {
+			return errors.New("accessToken cannot be empty")
+		}
+	default:
+		return errors.New("unknown auth type " + c.AuthType)
 	}
 
 	if c.APIURLString == "" {
@@ -82,61 +313,111 @@ func checkConfig(c *CloudControllerConfig) error {
 
 // Login to CC API and retrieve the access token.
 func (c *CloudController) Login() error {
+	return c.LoginContext(context.Background())
+}
 
-	parameters := url.Values{}
-	parameters.Set("username", c.Config.Username)
-	parameters.Set("password", c.Config.Password)
-	parameters.Set("scope", "")
-	parameters.Set("grant_type", "password")
+// LoginContext is like Login but uses the given context for all requests.
+func (c *CloudController) LoginContext(ctx context.Context) error {
 
-	info, err := c.GetV2Info()
+	authorizationEndpoint, err := c.authorizationEndpoint(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	authURLRelative := &url.URL{Path: "/oauth/token"}
-	authURL, err := url.Parse(info.AuthorizationEndpoint)
+	authURL, err := url.Parse(authorizationEndpoint)
 	if err != nil {
+		return err
+	}
+	c.TokenURL = authURL.ResolveReference(authURLRelative)
+
+	if c.Config.AuthType == AuthTypeAccessToken {
+		c.setAccessToken(c.Config.AccessToken, c.Config.RefreshToken)
 		return nil
 	}
-	authTokenURL := authURL.ResolveReference(authURLRelative)
-	req, err := http.NewRequest("POST", authTokenURL.String(), strings.NewReader(parameters.Encode()))
+
+	return c.requestToken(ctx, c.grantParameters())
+}
+
+// authorizationEndpoint returns the UAA login endpoint. It is taken from the
+// root endpoint of the cc API and from /v2/info for older foundations.
+func (c *CloudController) authorizationEndpoint(ctx context.Context) (string, error) {
+
+	rootInfo, err := c.GetRootInfoContext(ctx)
+	if err == nil && rootInfo.Links["login"] != nil && rootInfo.Links["login"].HRef != "" {
+		return rootInfo.Links["login"].HRef, nil
+	}
+
+	info, err := c.GetV2InfoContext(ctx)
 	if err != nil {
-		return nil
+		return "", err
+	}
+
+	return info.AuthorizationEndpoint, nil
+}
+
+// RootInfo represents the links to the APIs of a foundation as returned by
+// the root endpoint of the cc API.
+type RootInfo struct {
+	Links map[string]*v3.Link `json:"links"`
+}
+
+// GetRootInfo gets the links of the root endpoint of the cc API.
+func (c *CloudController) GetRootInfo() (*RootInfo, error) {
+	return c.GetRootInfoContext(context.Background())
+}
+
+// GetRootInfoContext is like GetRootInfo but uses the given context.
+func (c *CloudController) GetRootInfoContext(ctx context.Context) (*RootInfo, error) {
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.APIUrl.ResolveReference(&url.URL{Path: "/"}).String(), nil)
+	if err != nil {
+		return nil, err
 	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Host", info.AuthorizationEndpoint)
-	req.SetBasicAuth("cf", "")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.execute(req)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	err = checkResponse(resp)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var ati AccessTokenInfo
-	err = json.NewDecoder(resp.Body).Decode(&ati)
+	var i RootInfo
+	err = json.NewDecoder(resp.Body).Decode(&i)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	c.AccessToken = &ati
-	return nil
+	return &i, nil
 }
 
 // GetV2Info gets the general API info from the select cc API.
 func (c *CloudController) GetV2Info() (*V2Info, error) {
+	return c.GetV2InfoContext(context.Background())
+}
+
+// GetV2InfoContext is like GetV2Info but uses the given context.
+func (c *CloudController) GetV2InfoContext(ctx context.Context) (*V2Info, error) {
 	infoURLRelative := &url.URL{Path: "/v2/info"}
 	infoURL := c.APIUrl.ResolveReference(infoURLRelative)
 
-	req, err := http.NewRequest("GET", infoURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", infoURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.execute(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkResponse(resp)
 	if err != nil {
 		return nil, err
 	}
@@ -180,28 +461,150 @@ type AccessTokenInfo struct {
 
 // GetV3App
 func (c *CloudController) GetV3App(appID string) (*v3.App, error) {
-	apiURLRelative := &url.URL{Path: "/v3/apps/" + appID}
-	apiURL := c.APIUrl.ResolveReference(apiURLRelative)
+	return c.GetV3AppContext(context.Background(), appID)
+}
+
+// GetV3AppContext is like GetV3App but uses the given context.
+func (c *CloudController) GetV3AppContext(ctx context.Context, appID string) (*v3.App, error) {
 
-	req, err := http.NewRequest("GET", apiURL.String(), nil)
+	var a v3.App
+	err := c.GetJSON(ctx, "/v3/apps/"+appID, &a)
 	if err != nil {
 		return nil, err
 	}
+
+	return &a, nil
+}
+
+// GetJSON sends an authenticated GET request for the href and decodes the
+// JSON response into v. The href is either a path relative to the API URL,
+// optionally with query, or an absolute URL as found in v3 links. Absolute
+// URLs must point to the API host or to a trusted origin like the policy
+// server, so the access token is never sent elsewhere.
+func (c *CloudController) GetJSON(ctx context.Context, href string, v interface{}) error {
+	apiURLRelative, err := url.Parse(href)
+	if err != nil {
+		return err
+	}
+	apiURL := c.APIUrl.ResolveReference(apiURLRelative)
+
+	if !c.trusted(apiURL) {
+		return fmt.Errorf("refusing to send the access token to %s://%s", apiURL.Scheme, apiURL.Host)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL.String(), nil)
+	if err != nil {
+		return err
+	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken.AccessToken)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doAuthenticated(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	var a v3.App
-	err = json.NewDecoder(resp.Body).Decode(&a)
+	return json.NewDecoder(resp.Body).Decode(v)
+}
 
-	if err != nil {
-		return nil, err
+// trust allows GetJSON to send the access token to the scheme and host of
+// the URL.
+func (c *CloudController) trust(u *url.URL) {
+
+	c.mapMutex.Lock()
+	if c.trustedOrigins == nil {
+		c.trustedOrigins = make(map[string]bool)
 	}
+	c.trustedOrigins[u.Scheme+"://"+u.Host] = true
+	c.mapMutex.Unlock()
+}
 
-	return &a, nil
+// trusted reports whether the URL points to the API host or to a trusted
+// origin.
+func (c *CloudController) trusted(u *url.URL) bool {
+
+	if u.Scheme == c.APIUrl.Scheme && u.Host == c.APIUrl.Host {
+		return true
+	}
+
+	c.mapMutex.Lock()
+	defer c.mapMutex.Unlock()
+
+	return c.trustedOrigins[u.Scheme+"://"+u.Host]
+}
+
+// ListV3 calls fn with every resource of the v3 list at the given path, e.g.
+// "/v3/spaces", following all pages. Once the first page reports the total
+// number of pages the remaining pages are fetched concurrently by up to
+// Config.MaxConcurrency workers. The side-loaded resources requested via
+// query.Include are returned.
+//
+// A filter with more than v3MaxFilterValues values, e.g. the guids of many
+// apps, is split over several lists. Resources found by more than one of them
+// are passed to fn once.
+func (c *CloudController) ListV3(ctx context.Context, path string, query *v3.ListQuery, fn func(resource json.RawMessage) error) (v3.Included, error) {
+
+	name := longFilter(query)
+	if name == "" {
+		return v3.ListConcurrently(ctx, c, path, query, c.maxConcurrency(), fn)
+	}
+
+	values := query.Filters[name]
+	included := make(v3.Included)
+	seen := make(map[string]bool)
+
+	for start := 0; start < len(values); start += v3MaxFilterValues {
+		end := start + v3MaxFilterValues
+		if end > len(values) {
+			end = len(values)
+		}
+
+		chunk := *query
+		chunk.Filters = make(map[string][]string)
+		for filter, filterValues := range query.Filters {
+			chunk.Filters[filter] = filterValues
+		}
+		chunk.Filters[name] = values[start:end]
+
+		chunkIncluded, err := v3.ListConcurrently(ctx, c, path, &chunk, c.maxConcurrency(), func(resource json.RawMessage) error {
+			var r struct {
+				GUID string `json:"guid"`
+			}
+			if json.Unmarshal(resource, &r) == nil && r.GUID != "" {
+				if seen[r.GUID] {
+					return nil
+				}
+				seen[r.GUID] = true
+			}
+
+			return fn(resource)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for resourceType, resources := range chunkIncluded {
+			included[resourceType] = append(included[resourceType], resources...)
+		}
+	}
+
+	return included, nil
+}
+
+// longFilter returns the name of the filter of the query with the most
+// values if it has more than v3MaxFilterValues, otherwise "".
+func longFilter(query *v3.ListQuery) string {
+
+	if query == nil {
+		return ""
+	}
+
+	name := ""
+	for filter, values := range query.Filters {
+		if len(values) > v3MaxFilterValues && (name == "" || len(values) > len(query.Filters[name])) {
+			name = filter
+		}
+	}
+
+	return name
 }
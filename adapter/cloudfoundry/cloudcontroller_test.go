@@ -3,6 +3,7 @@ package cloudfoundry
 import (
 	"context"
 	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
 	. "github.com/smartystreets/goconvey/convey"
 	"io/ioutil"
 	"net"
@@ -235,6 +236,156 @@ func TestCloudControllerCreation(t *testing.T) {
 
 }
 
+func TestCloudControllerAuthTypes(t *testing.T) {
+
+	okInfoResponse := `{"authorization_endpoint": "http://uaa.mycloudcontroller"}`
+	okTokenResponse := `{"access_token": "clienttoken", "token_type": "bearer", "expires_in": 300}`
+
+	Convey("Given a config with an unknown auth type", t, func() {
+
+		Convey("When CloudController is created", func() {
+
+			Convey("Then an error message indicates the unknown auth type", func() {
+				_, err := NewCloudController(CloudControllerConfig{AuthType: "magic", APIURLString: "http://localhost"})
+
+				So(err.Error(), ShouldEqual, "unknown auth type magic")
+			})
+
+		})
+
+	})
+
+	Convey("Given a client_credentials config without client secret", t, func() {
+
+		Convey("When CloudController is created", func() {
+
+			Convey("Then an error message indicates the missing client secret", func() {
+				_, err := NewCloudController(CloudControllerConfig{AuthType: AuthTypeClientCredentials, ClientID: "ci", APIURLString: "http://localhost"})
+
+				So(err.Error(), ShouldEqual, "clientSecret cannot be empty")
+			})
+
+		})
+
+	})
+
+	Convey("Given a passcode config without passcode", t, func() {
+
+		Convey("When CloudController is created", func() {
+
+			Convey("Then an error message indicates the missing passcode", func() {
+				_, err := NewCloudController(CloudControllerConfig{AuthType: AuthTypePasscode, APIURLString: "http://localhost"})
+
+				So(err.Error(), ShouldEqual, "passcode cannot be empty")
+			})
+
+		})
+
+	})
+
+	Convey("Given a client_credentials config", t, func() {
+
+		Convey("When CloudController Login is requested", func() {
+
+			Convey("Then the client credentials grant is used with the configured client", func(c C) {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == "GET" {
+						w.Write([]byte(okInfoResponse))
+						return
+					}
+
+					user, secret, _ := r.BasicAuth()
+					c.So(user, ShouldEqual, "ci")
+					c.So(secret, ShouldEqual, "s3cr3t")
+					b, _ := ioutil.ReadAll(r.Body)
+					c.So(string(b), ShouldEqual, "grant_type=client_credentials")
+					w.Write([]byte(okTokenResponse))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc, _ := NewCloudController(CloudControllerConfig{
+					AuthType:     AuthTypeClientCredentials,
+					ClientID:     "ci",
+					ClientSecret: "s3cr3t",
+					APIURLString: "http://api.mycloudcontroller",
+				})
+				cc.httpClient = httpClient
+
+				err := cc.Login()
+				So(err, ShouldEqual, nil)
+				So(cc.AccessToken.AccessToken, ShouldEqual, "clienttoken")
+			})
+
+		})
+
+	})
+
+	Convey("Given a passcode config", t, func() {
+
+		Convey("When CloudController Login is requested", func() {
+
+			Convey("Then the passcode is sent with the cf client", func(c C) {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == "GET" {
+						w.Write([]byte(okInfoResponse))
+						return
+					}
+
+					c.So(r.Header.Get("authorization"), ShouldEqual, "Basic Y2Y6")
+					b, _ := ioutil.ReadAll(r.Body)
+					c.So(string(b), ShouldEqual, "grant_type=password&passcode=abc123")
+					w.Write([]byte(okTokenResponse))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc, _ := NewCloudController(CloudControllerConfig{
+					AuthType:     AuthTypePasscode,
+					Passcode:     "abc123",
+					APIURLString: "http://api.mycloudcontroller",
+				})
+				cc.httpClient = httpClient
+
+				err := cc.Login()
+				So(err, ShouldEqual, nil)
+				So(cc.AccessToken.AccessToken, ShouldEqual, "clienttoken")
+			})
+
+		})
+
+	})
+
+	Convey("Given an access_token config", t, func() {
+
+		Convey("When CloudController Login is requested", func() {
+
+			Convey("Then the pre-issued token is used without a token request", func(c C) {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					c.So(r.Method, ShouldEqual, "GET")
+					w.Write([]byte(okInfoResponse))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc, _ := NewCloudController(CloudControllerConfig{
+					AuthType:     AuthTypeAccessToken,
+					AccessToken:  "bearer preissued",
+					APIURLString: "http://api.mycloudcontroller",
+				})
+				cc.httpClient = httpClient
+
+				err := cc.Login()
+				So(err, ShouldEqual, nil)
+				So(cc.AccessToken.AccessToken, ShouldEqual, "preissued")
+			})
+
+		})
+
+	})
+
+}
+
 func testingHTTPClient(handler http.Handler) (*http.Client, func()) {
 	s := httptest.NewServer(handler)
 
@@ -248,3 +399,85 @@ func testingHTTPClient(handler http.Handler) (*http.Client, func()) {
 
 	return cli, s.Close
 }
+
+// testingCloudController returns a logged in cloud controller for the api
+// http://api.mycloudcontroller which sends its requests with the httpClient.
+// Username and password default to "u" and "p".
+func testingCloudController(httpClient *http.Client, config CloudControllerConfig) *CloudController {
+
+	if config.Username == "" {
+		config.Username = "u"
+		config.Password = "p"
+	}
+	config.APIURLString = "http://api.mycloudcontroller"
+
+	cc, _ := NewCloudController(config)
+	cc.httpClient = httpClient
+	cc.AccessToken = &AccessTokenInfo{AccessToken: "token"}
+
+	return cc
+}
+
+func TestGetJSON(t *testing.T) {
+
+	Convey("Given a href pointing to another host than the cc API", t, func() {
+
+		requested := false
+		httpClient, teardown := testingHTTPClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+			w.Write([]byte(`{}`))
+		}))
+		defer teardown()
+
+		cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+		Convey("When the href is requested", func() {
+
+			var v map[string]interface{}
+			err := cc.GetJSON(context.Background(), "http://evil.example.org/v3/apps?page=2", &v)
+
+			Convey("Then the access token is not sent", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "refusing to send the access token to http://evil.example.org")
+				So(requested, ShouldEqual, false)
+			})
+
+		})
+
+	})
+
+}
+
+func TestLoadedResources(t *testing.T) {
+
+	Convey("Given apps which are stored while they are read", t, func() {
+
+		cc := testingCloudController(nil, CloudControllerConfig{})
+
+		done := make(chan bool)
+		go func() {
+			for i := 0; i < 100; i++ {
+				storeV3(cc, &cc.V3AppMap, []*v3.App{{GUID: fmt.Sprintf("app-%d", i), Name: "my-app"}}, func(a *v3.App) string { return a.GUID })
+			}
+			close(done)
+		}()
+
+		Convey("When the loaded apps are read concurrently", func() {
+
+			for i := 0; i < 100; i++ {
+				cc.V3Apps()
+				cc.V3App("app-1")
+			}
+			<-done
+
+			Convey("Then all stored apps are returned", func() {
+				So(len(cc.V3Apps()), ShouldEqual, 100)
+				So(cc.V3App("app-1").Name, ShouldEqual, "my-app")
+				So(cc.V3App("unknown-guid"), ShouldEqual, nil)
+			})
+
+		})
+
+	})
+
+}
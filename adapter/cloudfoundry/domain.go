@@ -0,0 +1,17 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+)
+
+// QueryV3DomainsContext loads the shared and private domains matching the
+// query and adds them to V3DomainMap.
+func (c *CloudController) QueryV3DomainsContext(ctx context.Context, query *v3.ListQuery) ([]*v3.Domain, error) {
+	return queryV3(ctx, c, "/v3/domains", query, &c.V3DomainMap, func(d *v3.Domain) string { return d.GUID })
+}
+
+// V3Domain returns the loaded domain with the guid or nil.
+func (c *CloudController) V3Domain(guid string) *v3.Domain {
+	return lookupV3(c, &c.V3DomainMap, guid)
+}
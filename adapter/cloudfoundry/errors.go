@@ -0,0 +1,151 @@
+package cloudfoundry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Error kinds of unsuccessful cc API responses. Use errors.Is or the Is...
+// helpers to check the kind of an error returned by the CloudController.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+)
+
+// APIError is returned for unsuccessful responses of the cc API and the UAA.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Errors     []APIErrorDetail
+}
+
+// APIErrorDetail - A single error reported by the cc API.
+type APIErrorDetail struct {
+	Code   int    `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// apiErrorPayload covers the v2 ({code, description, error_code}), the v3
+// ({errors: [{code, title, detail}]}) and the UAA ({error,
+// error_description}) error shapes.
+type apiErrorPayload struct {
+	Code             int              `json:"code"`
+	Description      string           `json:"description"`
+	ErrorCode        string           `json:"error_code"`
+	Errors           []APIErrorDetail `json:"errors"`
+	Error            string           `json:"error"`
+	ErrorDescription string           `json:"error_description"`
+}
+
+func (e *APIError) Error() string {
+
+	var details []string
+	for _, d := range e.Errors {
+		switch {
+		case d.Title != "" && d.Detail != "":
+			details = append(details, d.Title+": "+d.Detail)
+		case d.Detail != "":
+			details = append(details, d.Detail)
+		default:
+			details = append(details, d.Title)
+		}
+	}
+
+	msg := fmt.Sprintf("%s %s failed with status %d", e.Method, e.URL, e.StatusCode)
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, "; ") + ")"
+	}
+
+	return msg
+}
+
+// Unwrap returns the error kind matching the status code.
+func (e *APIError) Unwrap() error {
+
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServerError
+	}
+
+	return nil
+}
+
+// IsNotFound reports whether the requested resource does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether the request was rejected because of a
+// missing or invalid access token.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether the user lacks the permissions for the request.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsRateLimited reports whether the request was throttled by the cc API.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError reports whether the cc API failed with a 5xx status.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
+
+// checkResponse returns an *APIError for responses without a 2xx status. The
+// body of such a response is consumed and closed.
+func checkResponse(resp *http.Response) error {
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	apiError := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		apiError.Method = resp.Request.Method
+		apiError.URL = resp.Request.URL.String()
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil || len(body) == 0 {
+		return apiError
+	}
+
+	var payload apiErrorPayload
+	if json.Unmarshal(body, &payload) != nil {
+		return apiError
+	}
+
+	switch {
+	case len(payload.Errors) > 0:
+		apiError.Errors = payload.Errors
+	case payload.ErrorCode != "" || payload.Description != "":
+		apiError.Errors = []APIErrorDetail{{Code: payload.Code, Title: payload.ErrorCode, Detail: payload.Description}}
+	case payload.Error != "":
+		apiError.Errors = []APIErrorDetail{{Title: payload.Error, Detail: payload.ErrorDescription}}
+	}
+
+	return apiError
+}
@@ -0,0 +1,90 @@
+package cloudfoundry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAPIErrors(t *testing.T) {
+
+	Convey("Given a cc API answering with a v3 error", t, func() {
+
+		Convey("When an app is requested", func() {
+
+			Convey("Then a not found error with the v3 error details is returned", func() {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "App not found"}]}`))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+				app, err := cc.GetV3App("unknown-guid")
+				So(app, ShouldEqual, nil)
+				So(IsNotFound(err), ShouldEqual, true)
+
+				apiError := err.(*APIError)
+				So(apiError.StatusCode, ShouldEqual, 404)
+				So(apiError.Errors[0].Code, ShouldEqual, 10010)
+				So(apiError.Errors[0].Title, ShouldEqual, "CF-ResourceNotFound")
+				So(err.Error(), ShouldEqual, "GET http://api.mycloudcontroller/v3/apps/unknown-guid failed with status 404 (CF-ResourceNotFound: App not found)")
+			})
+
+		})
+
+	})
+
+	Convey("Given a cc API answering with a v2 error", t, func() {
+
+		Convey("When a resource list is requested", func() {
+
+			Convey("Then a forbidden error with the v2 error details is returned", func() {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"code": 10003, "description": "You are not authorized to perform the requested action", "error_code": "CF-NotAuthorized"}`))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+				_, err := cc.GetResourceList("/v2/apps")
+				So(IsForbidden(err), ShouldEqual, true)
+				So(IsNotFound(err), ShouldEqual, false)
+
+				apiError := err.(*APIError)
+				So(apiError.Errors[0].Code, ShouldEqual, 10003)
+				So(apiError.Errors[0].Title, ShouldEqual, "CF-NotAuthorized")
+				So(apiError.Errors[0].Detail, ShouldEqual, "You are not authorized to perform the requested action")
+			})
+
+		})
+
+	})
+
+	Convey("Given a cc API failing with a server error", t, func() {
+
+		Convey("When the info is requested", func() {
+
+			Convey("Then a server error is returned", func() {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadGateway)
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc := testingCloudController(httpClient, CloudControllerConfig{RetryBackoff: time.Millisecond})
+
+				_, err := cc.GetV2Info()
+				So(IsServerError(err), ShouldEqual, true)
+			})
+
+		})
+
+	})
+
+}
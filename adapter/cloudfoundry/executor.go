@@ -0,0 +1,259 @@
+package cloudfoundry
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults for the retry settings of CloudControllerConfig.
+const (
+	DefaultMaxRetries      = 3
+	DefaultRetryBackoff    = 500 * time.Millisecond
+	DefaultMaxRetryBackoff = 30 * time.Second
+)
+
+// execute is the shared request executor for all requests to the cc API and
+// the UAA. Transient failures (429, 502, 503, 504 and network errors) are
+// retried with exponential backoff and jitter. Retry-After and the
+// X-RateLimit-* headers are honoured up to the max backoff, a failure asking
+// for a longer wait is returned right away. The client side request rate is
+// capped according to the config.
+func (c *CloudController) execute(req *http.Request) (*http.Response, error) {
+
+	maxRetries, backoff, maxBackoff := c.retrySettings()
+
+	for attempt := 0; ; attempt++ {
+
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		err := c.rateLimiter.wait(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(attemptReq)
+		if err == nil {
+			c.rateLimiter.observe(resp)
+		}
+
+		if attempt >= maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := backoffDuration(attempt, backoff, maxBackoff)
+		if resp != nil {
+			if retryAfter, ok := retryAfterDuration(resp); ok {
+				if retryAfter > maxBackoff {
+					return resp, nil
+				}
+				wait = retryAfter
+			}
+			resp.Body.Close()
+		}
+
+		err = sleep(req, wait)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retrySettings returns the retry settings of the config with defaults
+// applied.
+func (c *CloudController) retrySettings() (int, time.Duration, time.Duration) {
+
+	maxRetries := c.Config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	backoff := c.Config.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	maxBackoff := c.Config.MaxRetryBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxRetryBackoff
+	}
+
+	return maxRetries, backoff, maxBackoff
+}
+
+// retryable reports whether the request failed with a transient error.
+// Network errors are only retried for requests which can be sent again.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		return req.Body == nil || req.GetBody != nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoffDuration returns the exponential backoff for the given attempt with
+// a random jitter of up to half of the backoff.
+func backoffDuration(attempt int, backoff time.Duration, maxBackoff time.Duration) time.Duration {
+
+	wait := backoff
+	for i := 0; i < attempt && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// retryAfterDuration parses the Retry-After header, given either in seconds
+// or as HTTP date. Without Retry-After the X-RateLimit-Reset header of a 429
+// response is used. Times in the past mean no wait.
+func retryAfterDuration(resp *http.Response) (time.Duration, bool) {
+
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return nonNegative(time.Duration(seconds) * time.Second), true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return nonNegative(time.Until(date)), true
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if reset, ok := rateLimitReset(resp); ok {
+			return nonNegative(time.Until(reset)), true
+		}
+	}
+
+	return 0, false
+}
+
+// nonNegative returns the duration or zero if it is negative.
+func nonNegative(d time.Duration) time.Duration {
+
+	if d < 0 {
+		return 0
+	}
+
+	return d
+}
+
+// rateLimitReset parses the X-RateLimit-Reset header (unix time in seconds).
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(reset, 0), true
+}
+
+// sleep waits for the given duration or until the request is cancelled.
+func sleep(req *http.Request, d time.Duration) error {
+
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// rateLimiter spaces requests according to the configured requests per
+// second and pauses all requests once the cc API reports an exhausted rate
+// limit.
+type rateLimiter struct {
+	mutex       sync.Mutex
+	interval    time.Duration
+	maxPause    time.Duration
+	next        time.Time
+	pausedUntil time.Time
+}
+
+// newRateLimiter returns a rateLimiter for the given requests per second. A
+// value of zero or less means no client side limit. A pause for an exhausted
+// rate limit lasts at most maxPause.
+func newRateLimiter(requestsPerSecond float64, maxPause time.Duration) *rateLimiter {
+
+	l := &rateLimiter{maxPause: maxPause}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+
+	return l
+}
+
+// wait blocks until the next request may be sent.
+func (l *rateLimiter) wait(req *http.Request) error {
+
+	l.mutex.Lock()
+	now := time.Now()
+	start := now
+	if l.next.After(start) {
+		start = l.next
+	}
+	if l.pausedUntil.After(start) {
+		start = l.pausedUntil
+	}
+	l.next = start.Add(l.interval)
+	l.mutex.Unlock()
+
+	return sleep(req, start.Sub(now))
+}
+
+// observe pauses all requests until the reset time when the response reports
+// that no requests are remaining.
+func (l *rateLimiter) observe(resp *http.Response) {
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+
+	reset, ok := rateLimitReset(resp)
+	if !ok {
+		return
+	}
+
+	if maxReset := time.Now().Add(l.maxPause); reset.After(maxReset) {
+		reset = maxReset
+	}
+
+	l.mutex.Lock()
+	if reset.After(l.pausedUntil) {
+		l.pausedUntil = reset
+	}
+	l.mutex.Unlock()
+}
@@ -0,0 +1,195 @@
+package cloudfoundry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRequestExecutor(t *testing.T) {
+
+	Convey("Given a cc API failing with transient errors", t, func() {
+
+		requestCount := 0
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount++
+			if requestCount < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"guid": "app-guid", "name": "my_app"}`))
+		})
+		httpClient, teardown := testingHTTPClient(h)
+		defer teardown()
+
+		Convey("When an app is requested", func() {
+
+			Convey("Then the request is retried until it succeeds", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{RetryBackoff: time.Millisecond})
+
+				app, err := cc.GetV3App("app-guid")
+				So(err, ShouldEqual, nil)
+				So(app.Name, ShouldEqual, "my_app")
+				So(requestCount, ShouldEqual, 3)
+			})
+
+		})
+
+		Convey("When retries are disabled", func() {
+
+			Convey("Then the first failure is returned", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{MaxRetries: -1})
+
+				_, err := cc.GetV3App("app-guid")
+				So(IsServerError(err), ShouldEqual, true)
+				So(requestCount, ShouldEqual, 1)
+			})
+
+		})
+
+	})
+
+	Convey("Given a cc API which throttles the client", t, func() {
+
+		requestCount := 0
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount++
+			if requestCount == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{"guid": "app-guid", "name": "my_app"}`))
+		})
+		httpClient, teardown := testingHTTPClient(h)
+		defer teardown()
+
+		Convey("When an app is requested", func() {
+
+			Convey("Then the request is retried after the time given in Retry-After", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{RetryBackoff: time.Millisecond})
+
+				start := time.Now()
+				_, err := cc.GetV3App("app-guid")
+				So(err, ShouldEqual, nil)
+				So(requestCount, ShouldEqual, 2)
+				So(time.Since(start), ShouldBeGreaterThanOrEqualTo, time.Second)
+			})
+
+		})
+
+	})
+
+	Convey("Given a cc API which throttles the client for longer than the max backoff", t, func() {
+
+		requestCount := 0
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount++
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errors": [{"code": 10013, "title": "CF-RateLimitExceeded", "detail": "Rate Limit Exceeded"}]}`))
+		})
+		httpClient, teardown := testingHTTPClient(h)
+		defer teardown()
+
+		Convey("When an app is requested", func() {
+
+			Convey("Then the 429 failure is returned without waiting", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+				start := time.Now()
+				_, err := cc.GetV3App("app-guid")
+				So(err, ShouldNotEqual, nil)
+				So(requestCount, ShouldEqual, 1)
+				So(time.Since(start), ShouldBeLessThan, time.Second)
+			})
+
+		})
+
+	})
+
+	Convey("Given a cc API reporting a rate limit reset in the past", t, func() {
+
+		requestCount := 0
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount++
+			if requestCount == 1 {
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{"guid": "app-guid", "name": "my_app"}`))
+		})
+		httpClient, teardown := testingHTTPClient(h)
+		defer teardown()
+
+		Convey("When an app is requested", func() {
+
+			Convey("Then the request is retried right away", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+				start := time.Now()
+				_, err := cc.GetV3App("app-guid")
+				So(err, ShouldEqual, nil)
+				So(requestCount, ShouldEqual, 2)
+				So(time.Since(start), ShouldBeLessThan, time.Second)
+			})
+
+		})
+
+	})
+
+	Convey("Given a cc API reporting an exhausted rate limit", t, func() {
+
+		var requestTimes []time.Time
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestTimes = append(requestTimes, time.Now())
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10))
+			w.Write([]byte(`{"guid": "app-guid", "name": "my_app"}`))
+		})
+		httpClient, teardown := testingHTTPClient(h)
+		defer teardown()
+
+		Convey("When two apps are requested", func() {
+
+			Convey("Then the second request waits for the rate limit reset", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+				cc.GetV3App("app-guid")
+				cc.GetV3App("app-guid")
+				So(len(requestTimes), ShouldEqual, 2)
+				So(requestTimes[1].Unix(), ShouldBeGreaterThan, requestTimes[0].Unix())
+			})
+
+		})
+
+	})
+
+	Convey("Given a client side request rate cap", t, func() {
+
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"guid": "app-guid", "name": "my_app"}`))
+		})
+		httpClient, teardown := testingHTTPClient(h)
+		defer teardown()
+
+		Convey("When several apps are requested", func() {
+
+			Convey("Then the requests are spaced according to the cap", func() {
+				cc := testingCloudController(httpClient, CloudControllerConfig{RequestsPerSecond: 20})
+
+				start := time.Now()
+				for i := 0; i < 5; i++ {
+					cc.GetV3App("app-guid")
+				}
+				So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 200*time.Millisecond)
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,101 @@
+package cloudfoundry
+
+import (
+	"context"
+	"sync"
+)
+
+// GetInventory loads organizations, spaces, stacks, buildpacks and quotas of
+// the foundation in parallel from the v3 API.
+func (c *CloudController) GetInventory() error {
+	return c.GetInventoryContext(context.Background())
+}
+
+// GetInventoryContext is like GetInventory but uses the given context.
+func (c *CloudController) GetInventoryContext(ctx context.Context) error {
+	return c.loadParallel(ctx,
+		c.GetV3OrganizationsContext,
+		c.GetV3SpacesContext,
+		c.GetV3StacksContext,
+		c.GetV3BuildpacksContext,
+		c.GetV3OrganizationQuotasContext,
+		c.GetV3SpaceQuotasContext,
+	)
+}
+
+// GetV3AppResources loads the processes, sidecars, recent tasks, lineage,
+// routes and service bindings of the app in parallel. A limit of zero loads
+// DefaultRecentTasks tasks.
+func (c *CloudController) GetV3AppResources(appGUID string, limit int) error {
+	return c.GetV3AppResourcesContext(context.Background(), appGUID, limit)
+}
+
+// GetV3AppResourcesContext is like GetV3AppResources but uses the given
+// context.
+func (c *CloudController) GetV3AppResourcesContext(ctx context.Context, appGUID string, limit int) error {
+	return c.loadParallel(ctx,
+		func(ctx context.Context) error {
+			_, err := c.GetV3AppProcessesContext(ctx, appGUID)
+			return err
+		},
+		func(ctx context.Context) error {
+			_, err := c.GetV3AppSidecarsContext(ctx, appGUID)
+			return err
+		},
+		func(ctx context.Context) error {
+			_, err := c.GetV3AppTasksContext(ctx, appGUID, limit)
+			return err
+		},
+		func(ctx context.Context) error {
+			_, err := c.GetV3AppLineageContext(ctx, appGUID)
+			return err
+		},
+		func(ctx context.Context) error {
+			_, err := c.GetV3AppRoutesContext(ctx, appGUID)
+			return err
+		},
+		func(ctx context.Context) error {
+			_, err := c.GetV3AppServiceBindingsContext(ctx, appGUID)
+			return err
+		},
+	)
+}
+
+// loadParallel runs the given loaders concurrently. The first failing loader
+// cancels all others and its error is returned.
+func (c *CloudController) loadParallel(ctx context.Context, loaders ...func(context.Context) error) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var loadErr error
+
+	var wg sync.WaitGroup
+	for _, loader := range loaders {
+		wg.Add(1)
+		go func(load func(context.Context) error) {
+			defer wg.Done()
+			err := load(ctx)
+			if err != nil {
+				once.Do(func() {
+					loadErr = err
+					cancel()
+				})
+			}
+		}(loader)
+	}
+	wg.Wait()
+
+	return loadErr
+}
+
+// maxConcurrency returns the configured worker limit for parallel paging.
+func (c *CloudController) maxConcurrency() int {
+
+	if c.Config.MaxConcurrency > 0 {
+		return c.Config.MaxConcurrency
+	}
+
+	return DefaultMaxConcurrency
+}
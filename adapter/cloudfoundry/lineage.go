@@ -0,0 +1,91 @@
+package cloudfoundry
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// GetV3AppLineage - Loads current droplet, droplets, packages and deployed
+// revisions of the app and adds them to V3AppLineageMap.
+func (c *CloudController) GetV3AppLineage(appGUID string) (*v3.AppLineage, error) {
+	return c.GetV3AppLineageContext(context.Background(), appGUID)
+}
+
+// GetV3AppLineageContext is like GetV3AppLineage but uses the given context.
+func (c *CloudController) GetV3AppLineageContext(ctx context.Context, appGUID string) (*v3.AppLineage, error) {
+
+	lineage := &v3.AppLineage{}
+
+	var current v3.Droplet
+	err := c.GetJSON(ctx, "/v3/apps/"+appGUID+"/droplets/current", &current)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		lineage.CurrentDroplet = &current
+	}
+
+	newestFirst := &v3.ListQuery{PerPage: v3MaxPerPage, OrderBy: "-created_at"}
+
+	_, err = c.ListV3(ctx, "/v3/apps/"+appGUID+"/droplets", newestFirst, func(resource json.RawMessage) error {
+		d := new(v3.Droplet)
+		err := json.Unmarshal(resource, d)
+		if err != nil {
+			return err
+		}
+
+		lineage.Droplets = append(lineage.Droplets, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.ListV3(ctx, "/v3/apps/"+appGUID+"/packages", newestFirst, func(resource json.RawMessage) error {
+		p := new(v3.Package)
+		err := json.Unmarshal(resource, p)
+		if err != nil {
+			return err
+		}
+
+		lineage.Packages = append(lineage.Packages, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.ListV3(ctx, "/v3/apps/"+appGUID+"/revisions/deployed", &v3.ListQuery{PerPage: v3MaxPerPage}, func(resource json.RawMessage) error {
+		r := new(v3.Revision)
+		err := json.Unmarshal(resource, r)
+		if err != nil {
+			return err
+		}
+
+		lineage.DeployedRevisions = append(lineage.DeployedRevisions, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(lineage.DeployedRevisions, func(i, j int) bool {
+		return lineage.DeployedRevisions[i].Version < lineage.DeployedRevisions[j].Version
+	})
+
+	c.mapMutex.Lock()
+	if c.V3AppLineageMap == nil {
+		resultMap := make(map[string]*v3.AppLineage)
+		c.V3AppLineageMap = &resultMap
+	}
+	(*c.V3AppLineageMap)[appGUID] = lineage
+	c.mapMutex.Unlock()
+
+	return lineage, nil
+}
+
+// V3AppLineage returns the loaded lineage of the app or nil.
+func (c *CloudController) V3AppLineage(appGUID string) *v3.AppLineage {
+	return lookupV3(c, &c.V3AppLineageMap, appGUID)
+}
@@ -0,0 +1,237 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// networkPolicyMaxIDs limits the number of app guids sent with one request to
+// the policy server to keep the url short.
+const networkPolicyMaxIDs = 100
+
+// NetworkPolicy - A container-to-container network policy allowing the
+// source app to reach the destination app on the given ports.
+type NetworkPolicy struct {
+	Source      *NetworkPolicySource      `json:"source"`
+	Destination *NetworkPolicyDestination `json:"destination"`
+}
+
+// NetworkPolicySource
+type NetworkPolicySource struct {
+	ID  string `json:"id"` //guid of the app
+	Tag string `json:"tag"`
+}
+
+// NetworkPolicyDestination
+type NetworkPolicyDestination struct {
+	ID       string              `json:"id"` //guid of the app
+	Tag      string              `json:"tag"`
+	Protocol string              `json:"protocol"` //"tcp" or "udp"
+	Ports    *NetworkPolicyPorts `json:"ports"`
+}
+
+// NetworkPolicyPorts
+type NetworkPolicyPorts struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// NetworkPolicies - The response of the policy server.
+type NetworkPolicies struct {
+	TotalPolicies int              `json:"total_policies"`
+	Policies      []*NetworkPolicy `json:"policies"`
+}
+
+// SourceGUID returns the guid of the source app.
+func (p *NetworkPolicy) SourceGUID() string {
+
+	if p.Source == nil {
+		return ""
+	}
+
+	return p.Source.ID
+}
+
+// DestinationGUID returns the guid of the destination app.
+func (p *NetworkPolicy) DestinationGUID() string {
+
+	if p.Destination == nil {
+		return ""
+	}
+
+	return p.Destination.ID
+}
+
+// PortRange returns the ports of the destination, e.g. "8080" or
+// "8080-8090".
+func (p *NetworkPolicy) PortRange() string {
+
+	if p.Destination == nil || p.Destination.Ports == nil {
+		return ""
+	}
+
+	ports := p.Destination.Ports
+	if ports.End == 0 || ports.End == ports.Start {
+		return strconv.Itoa(ports.Start)
+	}
+
+	return strconv.Itoa(ports.Start) + "-" + strconv.Itoa(ports.End)
+}
+
+// Description returns protocol and ports of the policy, e.g. "tcp 8080".
+func (p *NetworkPolicy) Description() string {
+
+	if p.Destination == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(p.Destination.Protocol + " " + p.PortRange())
+}
+
+// Key identifies the policy within NetworkPolicyMap.
+func (p *NetworkPolicy) Key() string {
+	return p.SourceGUID() + "->" + p.DestinationGUID() + ":" + p.Description()
+}
+
+// GetNetworkPolicies - Loads the network policies from the policy server
+// in which any of the apps is source or destination. Without app guids all
+// policies visible to the user are loaded.
+func (c *CloudController) GetNetworkPolicies(appGUIDs ...string) ([]*NetworkPolicy, error) {
+	return c.GetNetworkPoliciesContext(context.Background(), appGUIDs...)
+}
+
+// GetNetworkPoliciesContext is like GetNetworkPolicies but uses the given
+// context.
+func (c *CloudController) GetNetworkPoliciesContext(ctx context.Context, appGUIDs ...string) ([]*NetworkPolicy, error) {
+
+	endpoint, err := c.networkPolicyEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := append([]string(nil), appGUIDs...)
+	sort.Strings(ids)
+
+	var result []*NetworkPolicy
+	for start := 0; start == 0 || start < len(ids); start += networkPolicyMaxIDs {
+		href := endpoint + "/policies"
+
+		end := start + networkPolicyMaxIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if end > start {
+			href += "?" + url.Values{"id": {strings.Join(ids[start:end], ",")}}.Encode()
+		}
+
+		var policies NetworkPolicies
+		err = c.GetJSON(ctx, href, &policies)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, policies.Policies...)
+	}
+
+	c.mapMutex.Lock()
+	if c.NetworkPolicyMap == nil {
+		resultMap := make(map[string]*NetworkPolicy)
+		c.NetworkPolicyMap = &resultMap
+	}
+	for _, p := range result {
+		(*c.NetworkPolicyMap)[p.Key()] = p
+	}
+	c.mapMutex.Unlock()
+
+	return result, nil
+}
+
+// GetAppNetworkPoliciesContext loads the network policies of the apps like
+// GetNetworkPoliciesContext and adds the peer apps of the policies which are
+// not loaded yet to V3AppMap.
+func (c *CloudController) GetAppNetworkPoliciesContext(ctx context.Context, appGUIDs ...string) ([]*NetworkPolicy, error) {
+
+	policies, err := c.GetNetworkPoliciesContext(ctx, appGUIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, guid := range appGUIDs {
+		known[guid] = true
+	}
+
+	c.mapMutex.Lock()
+	peerGUIDs := make(map[string]bool)
+	for _, p := range policies {
+		for _, guid := range []string{p.SourceGUID(), p.DestinationGUID()} {
+			if guid == "" || known[guid] {
+				continue
+			}
+			if c.V3AppMap != nil && (*c.V3AppMap)[guid] != nil {
+				continue
+			}
+			peerGUIDs[guid] = true
+		}
+	}
+	c.mapMutex.Unlock()
+
+	if len(peerGUIDs) > 0 {
+		_, err = c.QueryV3AppsContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(peerGUIDs)...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return policies, nil
+}
+
+// networkPolicyEndpoint returns the external api of the policy server. It is
+// taken from the root endpoint of the cc API and defaults to
+// /networking/v1/external on the API host. The announced policy server is
+// trusted to receive the access token.
+func (c *CloudController) networkPolicyEndpoint(ctx context.Context) (string, error) {
+
+	rootInfo, err := c.GetRootInfoContext(ctx)
+	if err == nil && rootInfo.Links["network_policy_v1"] != nil && rootInfo.Links["network_policy_v1"].HRef != "" {
+		endpoint := strings.TrimSuffix(rootInfo.Links["network_policy_v1"].HRef, "/")
+
+		endpointURL, err := url.Parse(endpoint)
+		if err != nil {
+			return "", err
+		}
+		c.trust(endpointURL)
+
+		return endpoint, nil
+	}
+	if err != nil && !IsNotFound(err) {
+		return "", err
+	}
+
+	return c.APIUrl.ResolveReference(&url.URL{Path: "/networking/v1/external"}).String(), nil
+}
+
+// NetworkPoliciesOf returns the loaded policies in which the app is source
+// or destination, sorted by source, destination and ports.
+func (c *CloudController) NetworkPoliciesOf(appGUID string) []*NetworkPolicy {
+
+	var result []*NetworkPolicy
+
+	c.mapMutex.Lock()
+	if c.NetworkPolicyMap != nil {
+		for _, p := range *c.NetworkPolicyMap {
+			if p.SourceGUID() == appGUID || p.DestinationGUID() == appGUID {
+				result = append(result, p)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key() < result[j].Key() })
+
+	return result
+}
@@ -0,0 +1,60 @@
+package cloudfoundry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+func TestNetworkPolicies(t *testing.T) {
+
+	Convey("Given a policy server announced by the root endpoint", t, func() {
+
+		var requestedURL, authorization string
+		httpClient, teardown := testingHTTPClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				w.Write([]byte(`{"links": {"network_policy_v1": {"href": "http://policy.mycloudcontroller/networking/v1/external"}}}`))
+			case "/networking/v1/external/policies":
+				requestedURL = r.URL.String()
+				authorization = r.Header.Get("Authorization")
+				w.Write([]byte(`{"total_policies": 2, "policies": [
+					{"source": {"id": "frontend-guid"}, "destination": {"id": "backend-guid", "protocol": "tcp", "ports": {"start": 8080, "end": 8080}}},
+					{"source": {"id": "backend-guid"}, "destination": {"id": "db-app-guid", "protocol": "udp", "ports": {"start": 5000, "end": 5010}}}
+				]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer teardown()
+
+		Convey("When the policies of an app are requested", func() {
+
+			cc := testingCloudController(httpClient, CloudControllerConfig{})
+			policies, err := cc.GetNetworkPolicies("frontend-guid", "backend-guid")
+
+			Convey("Then the policies are requested with the token of the cloud controller", func() {
+				So(err, ShouldEqual, nil)
+				So(requestedURL, ShouldEqual, "/networking/v1/external/policies?id=backend-guid%2Cfrontend-guid")
+				So(authorization, ShouldEqual, "Bearer token")
+				So(len(policies), ShouldEqual, 2)
+			})
+
+			Convey("Then the policies are described by protocol and port range", func() {
+				So(policies[0].Description(), ShouldEqual, "tcp 8080")
+				So(policies[1].Description(), ShouldEqual, "udp 5000-5010")
+			})
+
+			Convey("Then the inbound and outbound policies of an app can be selected", func() {
+				backendPolicies := cc.NetworkPoliciesOf("backend-guid")
+				So(len(backendPolicies), ShouldEqual, 2)
+				So(backendPolicies[0].SourceGUID(), ShouldEqual, "backend-guid")
+				So(backendPolicies[1].SourceGUID(), ShouldEqual, "frontend-guid")
+				So(len(cc.NetworkPoliciesOf("db-app-guid")), ShouldEqual, 1)
+			})
+
+		})
+
+	})
+
+}
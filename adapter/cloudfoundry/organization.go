@@ -1,8 +1,11 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
 )
 
 // OrganizationEntity
@@ -40,8 +43,16 @@ type OrganizationInfo struct {
 
 // GetOrganizations
 func (c *CloudController) GetOrganizations() error {
+	return c.GetOrganizationsContext(context.Background())
+}
+
+// GetOrganizationsContext is like GetOrganizations but uses the given context.
+func (c *CloudController) GetOrganizationsContext(ctx context.Context) error {
 
-	organizationResources, err := c.GetResourceList("/v2/organizations")
+	organizationResources, err := c.GetResourceListContext(ctx, "/v2/organizations")
+	if err != nil {
+		return err
+	}
 
 	resultMap := make(map[string]*OrganizationInfo)
 
@@ -70,3 +81,55 @@ func (c *CloudController) GetOrganizations() error {
 	return err
 
 }
+
+// GetV3Organizations - Loads all organizations from the v3 API.
+func (c *CloudController) GetV3Organizations() error {
+	return c.GetV3OrganizationsContext(context.Background())
+}
+
+// GetV3OrganizationsContext is like GetV3Organizations but uses the given context.
+func (c *CloudController) GetV3OrganizationsContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/organizations", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3OrganizationMap, func(o *v3.Organization) string { return o.GUID })
+	return err
+}
+
+// QueryV3OrganizationsContext loads the organizations matching the query, e.g. a
+// label_selector, and adds them to V3OrganizationMap.
+func (c *CloudController) QueryV3OrganizationsContext(ctx context.Context, query *v3.ListQuery) ([]*v3.Organization, error) {
+	return queryV3(ctx, c, "/v3/organizations", query, &c.V3OrganizationMap, func(o *v3.Organization) string { return o.GUID })
+}
+
+// SelectV3Organizations returns the already loaded organizations whose labels match
+// the label selector, sorted by name.
+func (c *CloudController) SelectV3Organizations(selector string) ([]*v3.Organization, error) {
+
+	labelSelector, err := v3.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*v3.Organization
+	c.mapMutex.Lock()
+	if c.V3OrganizationMap != nil {
+		for _, o := range *c.V3OrganizationMap {
+			if labelSelector.Matches(o.Metadata.GetLabels()) {
+				result = append(result, o)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// V3Organizations returns the loaded organizations in no particular order.
+func (c *CloudController) V3Organizations() []*v3.Organization {
+	return valuesV3(c, &c.V3OrganizationMap)
+}
+
+// V3Organization returns the loaded organization with the guid or nil.
+func (c *CloudController) V3Organization(guid string) *v3.Organization {
+	return lookupV3(c, &c.V3OrganizationMap, guid)
+}
@@ -0,0 +1,113 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// GetV3AppProcesses - Loads the processes of the app together with the
+// stats of their instances.
+func (c *CloudController) GetV3AppProcesses(appGUID string) ([]*v3.Process, error) {
+	return c.GetV3AppProcessesContext(context.Background(), appGUID)
+}
+
+// GetV3AppProcessesContext is like GetV3AppProcesses but uses the given
+// context.
+func (c *CloudController) GetV3AppProcessesContext(ctx context.Context, appGUID string) ([]*v3.Process, error) {
+
+	processes, err := listV3[v3.Process](ctx, c, "/v3/apps/"+appGUID+"/processes", &v3.ListQuery{PerPage: v3MaxPerPage})
+	if err != nil {
+		return nil, err
+	}
+
+	// the cc API refers to the app of a process only by link
+	for _, p := range processes {
+		if p.AppGUID() == "" {
+			if p.Relationships == nil {
+				p.Relationships = &v3.ProcessRelationships{}
+			}
+			p.Relationships.App = appRelationship(appGUID)
+		}
+	}
+
+	stats := make(map[string][]*v3.ProcessInstanceStats)
+	for _, p := range processes {
+		s, err := c.GetV3ProcessStatsContext(ctx, p.GUID)
+		if err != nil {
+			return nil, err
+		}
+		stats[p.GUID] = s
+	}
+
+	c.mapMutex.Lock()
+	if c.V3ProcessMap == nil {
+		resultMap := make(map[string]*v3.Process)
+		c.V3ProcessMap = &resultMap
+	}
+	if c.V3ProcessStatsMap == nil {
+		resultMap := make(map[string][]*v3.ProcessInstanceStats)
+		c.V3ProcessStatsMap = &resultMap
+	}
+	for _, p := range processes {
+		(*c.V3ProcessMap)[p.GUID] = p
+		(*c.V3ProcessStatsMap)[p.GUID] = stats[p.GUID]
+	}
+	c.mapMutex.Unlock()
+
+	return processes, nil
+}
+
+// GetV3ProcessStatsContext loads the stats of all instances of the process,
+// sorted by index.
+func (c *CloudController) GetV3ProcessStatsContext(ctx context.Context, processGUID string) ([]*v3.ProcessInstanceStats, error) {
+
+	var stats v3.ProcessStats
+	err := c.GetJSON(ctx, "/v3/processes/"+processGUID+"/stats", &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats.Resources, func(i, j int) bool { return stats.Resources[i].Index < stats.Resources[j].Index })
+
+	return stats.Resources, nil
+}
+
+// ProcessesOf returns the loaded processes of the app. The web process comes
+// first, the others are sorted by type.
+func (c *CloudController) ProcessesOf(appGUID string) []*v3.Process {
+
+	var result []*v3.Process
+
+	c.mapMutex.Lock()
+	if c.V3ProcessMap != nil {
+		for _, p := range *c.V3ProcessMap {
+			if p.AppGUID() == appGUID {
+				result = append(result, p)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Type == "web") != (result[j].Type == "web") {
+			return result[i].Type == "web"
+		}
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+// ProcessStatsOf returns the loaded instance stats of the process.
+func (c *CloudController) ProcessStatsOf(processGUID string) []*v3.ProcessInstanceStats {
+
+	c.mapMutex.Lock()
+	defer c.mapMutex.Unlock()
+
+	if c.V3ProcessStatsMap == nil {
+		return nil
+	}
+
+	return (*c.V3ProcessStatsMap)[processGUID]
+}
@@ -1,8 +1,10 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
 )
 
 // QuotaDefinitionEntity - Entity data for Org Quota Definitions.
@@ -29,8 +31,16 @@ type QuotaDefinitionInfo struct {
 
 // GetQuotaDefinitions
 func (c *CloudController) GetQuotaDefinitions() error {
+	return c.GetQuotaDefinitionsContext(context.Background())
+}
+
+// GetQuotaDefinitionsContext is like GetQuotaDefinitions but uses the given context.
+func (c *CloudController) GetQuotaDefinitionsContext(ctx context.Context) error {
 
-	quotaDefinitionResources, err := c.GetResourceList("/v2/quota_definitions")
+	quotaDefinitionResources, err := c.GetResourceListContext(ctx, "/v2/quota_definitions")
+	if err != nil {
+		return err
+	}
 
 	resultMap := make(map[string]*QuotaDefinitionInfo)
 
@@ -59,3 +69,37 @@ func (c *CloudController) GetQuotaDefinitions() error {
 	return err
 
 }
+
+// GetV3OrganizationQuotas - Loads all organization quotas from the v3 API.
+func (c *CloudController) GetV3OrganizationQuotas() error {
+	return c.GetV3OrganizationQuotasContext(context.Background())
+}
+
+// GetV3OrganizationQuotasContext is like GetV3OrganizationQuotas but uses the given context.
+func (c *CloudController) GetV3OrganizationQuotasContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/organization_quotas", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3OrganizationQuotaMap, func(o *v3.OrganizationQuota) string { return o.GUID })
+	return err
+}
+
+// GetV3SpaceQuotas - Loads all space quotas from the v3 API.
+func (c *CloudController) GetV3SpaceQuotas() error {
+	return c.GetV3SpaceQuotasContext(context.Background())
+}
+
+// GetV3SpaceQuotasContext is like GetV3SpaceQuotas but uses the given context.
+func (c *CloudController) GetV3SpaceQuotasContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/space_quotas", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3SpaceQuotaMap, func(s *v3.SpaceQuota) string { return s.GUID })
+	return err
+}
+
+// V3OrganizationQuota returns the loaded organization quota with the guid or nil.
+func (c *CloudController) V3OrganizationQuota(guid string) *v3.OrganizationQuota {
+	return lookupV3(c, &c.V3OrganizationQuotaMap, guid)
+}
+
+// V3SpaceQuota returns the loaded space quota with the guid or nil.
+func (c *CloudController) V3SpaceQuota(guid string) *v3.SpaceQuota {
+	return lookupV3(c, &c.V3SpaceQuotaMap, guid)
+}
@@ -0,0 +1,226 @@
+package cloudfoundry
+
+import (
+	"context"
+	"errors"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strings"
+)
+
+// ErrAmbiguous is the error kind for names matching several resources. Use
+// errors.Is or IsAmbiguous to check for it.
+var ErrAmbiguous = errors.New("ambiguous")
+
+// IsAmbiguous reports whether the error is caused by a name matching
+// several resources.
+func IsAmbiguous(err error) bool {
+	return errors.Is(err, ErrAmbiguous)
+}
+
+// NameError is returned when a name does not resolve to exactly one
+// resource. It unwraps to ErrNotFound or ErrAmbiguous.
+type NameError struct {
+	// Type of the resource, e.g. "organization", "space" or "app".
+	Type string
+	Name string
+	// Scope describes where the resource was searched, e.g.
+	// `space "dev" of organization "my-org"`. Empty means the foundation.
+	Scope string
+	// GUIDs of all matching resources.
+	GUIDs []string
+}
+
+func (e *NameError) Error() string {
+
+	msg := e.Type + " \"" + e.Name + "\""
+	if len(e.GUIDs) == 0 {
+		msg += " not found"
+	} else {
+		msg += " is ambiguous"
+	}
+
+	if e.Scope != "" {
+		msg += " in " + e.Scope
+	}
+
+	if len(e.GUIDs) > 0 {
+		msg += ", matches " + strings.Join(e.GUIDs, ", ")
+	}
+
+	return msg
+}
+
+// Unwrap returns ErrNotFound or ErrAmbiguous.
+func (e *NameError) Unwrap() error {
+
+	if len(e.GUIDs) == 0 {
+		return ErrNotFound
+	}
+
+	return ErrAmbiguous
+}
+
+// ResolveOrganization returns the organization with the name.
+func (c *CloudController) ResolveOrganization(orgName string) (*v3.Organization, error) {
+	return c.ResolveOrganizationContext(context.Background(), orgName)
+}
+
+// ResolveOrganizationContext is like ResolveOrganization but uses the given
+// context.
+func (c *CloudController) ResolveOrganizationContext(ctx context.Context, orgName string) (*v3.Organization, error) {
+
+	err := checkName("organization", orgName)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := c.QueryV3OrganizationsContext(ctx, (&v3.ListQuery{}).Filter("names", orgName))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orgs) != 1 {
+		nameError := &NameError{Type: "organization", Name: orgName}
+		for _, o := range orgs {
+			nameError.GUIDs = append(nameError.GUIDs, o.GUID)
+		}
+		return nil, nameError
+	}
+
+	return orgs[0], nil
+}
+
+// ResolveSpace returns the space with the name in the organization with the
+// name. Without organization name the space is searched in the targeted
+// organization, or in all organizations if there is no target.
+func (c *CloudController) ResolveSpace(orgName string, spaceName string) (*v3.Space, error) {
+	return c.ResolveSpaceContext(context.Background(), orgName, spaceName)
+}
+
+// ResolveSpaceContext is like ResolveSpace but uses the given context.
+func (c *CloudController) ResolveSpaceContext(ctx context.Context, orgName string, spaceName string) (*v3.Space, error) {
+
+	err := checkName("space", spaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	orgName, _ = c.targetScope(orgName, spaceName)
+
+	query := (&v3.ListQuery{}).Filter("names", spaceName)
+	if orgName != "" {
+		org, err := c.ResolveOrganizationContext(ctx, orgName)
+		if err != nil {
+			return nil, err
+		}
+		query.Filter("organization_guids", org.GUID)
+	}
+
+	spaces, err := c.QueryV3SpacesContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spaces) != 1 {
+		nameError := &NameError{Type: "space", Name: spaceName, Scope: nameScope(orgName, "")}
+		for _, s := range spaces {
+			nameError.GUIDs = append(nameError.GUIDs, s.GUID)
+		}
+		return nil, nameError
+	}
+
+	return spaces[0], nil
+}
+
+// ResolveApp returns the app with the name in the space with the name of the
+// organization with the name. Without organization name the targeted
+// organization and space are used. Empty organization or space names without
+// target widen the search to all organizations or spaces.
+func (c *CloudController) ResolveApp(orgName string, spaceName string, appName string) (*v3.App, error) {
+	return c.ResolveAppContext(context.Background(), orgName, spaceName, appName)
+}
+
+// ResolveAppContext is like ResolveApp but uses the given context.
+func (c *CloudController) ResolveAppContext(ctx context.Context, orgName string, spaceName string, appName string) (*v3.App, error) {
+
+	err := checkName("app", appName)
+	if err != nil {
+		return nil, err
+	}
+
+	orgName, spaceName = c.targetScope(orgName, spaceName)
+
+	query := (&v3.ListQuery{}).Filter("names", appName)
+	switch {
+	case spaceName != "":
+		space, err := c.ResolveSpaceContext(ctx, orgName, spaceName)
+		if err != nil {
+			return nil, err
+		}
+		query.Filter("space_guids", space.GUID)
+	case orgName != "":
+		org, err := c.ResolveOrganizationContext(ctx, orgName)
+		if err != nil {
+			return nil, err
+		}
+		query.Filter("organization_guids", org.GUID)
+	}
+
+	apps, err := c.QueryV3AppsContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(apps) != 1 {
+		nameError := &NameError{Type: "app", Name: appName, Scope: nameScope(orgName, spaceName)}
+		for _, a := range apps {
+			nameError.GUIDs = append(nameError.GUIDs, a.GUID)
+		}
+		return nil, nameError
+	}
+
+	return apps[0], nil
+}
+
+// checkName rejects names containing a comma. The names filter of the v3 API
+// separates names by commas, so such a name would be searched as several
+// names.
+func checkName(resourceType string, name string) error {
+
+	if strings.Contains(name, ",") {
+		return errors.New(resourceType + " name \"" + name + "\" must not contain a comma")
+	}
+
+	return nil
+}
+
+// targetScope fills in the org and space targeted by the cf CLI when no
+// organization name is given. The targeted space is only used if no space
+// name is given either.
+func (c *CloudController) targetScope(orgName string, spaceName string) (string, string) {
+
+	if orgName != "" {
+		return orgName, spaceName
+	}
+
+	if spaceName == "" {
+		spaceName = c.TargetSpaceName
+	}
+
+	return c.TargetOrganizationName, spaceName
+}
+
+// nameScope describes the organization and space a resource is searched in.
+func nameScope(orgName string, spaceName string) string {
+
+	switch {
+	case orgName != "" && spaceName != "":
+		return "space \"" + spaceName + "\" of organization \"" + orgName + "\""
+	case spaceName != "":
+		return "space \"" + spaceName + "\""
+	case orgName != "":
+		return "organization \"" + orgName + "\""
+	}
+
+	return ""
+}
@@ -0,0 +1,130 @@
+package cloudfoundry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+func TestResolveNames(t *testing.T) {
+
+	responses := map[string]string{
+		"/v3/organizations?names=my-org":                    `{"resources": [{"guid": "org-guid", "name": "my-org"}]}`,
+		"/v3/spaces?names=dev&organization_guids=org-guid":  `{"resources": [{"guid": "space-guid", "name": "dev"}]}`,
+		"/v3/spaces?names=dev":                              `{"resources": [{"guid": "space-guid", "name": "dev"}, {"guid": "other-space-guid", "name": "dev"}]}`,
+		"/v3/apps?names=my-app&space_guids=space-guid":      `{"resources": [{"guid": "app-guid", "name": "my-app"}]}`,
+		"/v3/apps?names=my-app&organization_guids=org-guid": `{"resources": [{"guid": "app-guid", "name": "my-app"}, {"guid": "other-app-guid", "name": "my-app"}]}`,
+		"/v3/apps?names=unknown-app&space_guids=space-guid": `{"resources": []}`,
+		"/v3/organizations?names=unknown-org":               `{"resources": []}`,
+	}
+
+	httpClient, teardown := testingHTTPClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path+"?"+r.URL.RawQuery]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	defer teardown()
+
+	Convey("Given an app in a space of an organization", t, func() {
+
+		cc := testingCloudController(httpClient, CloudControllerConfig{})
+
+		Convey("When the app is resolved by org, space and app name", func() {
+
+			app, err := cc.ResolveApp("my-org", "dev", "my-app")
+
+			Convey("Then the app is returned", func() {
+				So(err, ShouldEqual, nil)
+				So(app.GUID, ShouldEqual, "app-guid")
+			})
+
+		})
+
+		Convey("When an unknown app is resolved", func() {
+
+			_, err := cc.ResolveApp("my-org", "dev", "unknown-app")
+
+			Convey("Then a not found error names the app and its scope", func() {
+				So(IsNotFound(err), ShouldEqual, true)
+				So(err.Error(), ShouldEqual, `app "unknown-app" not found in space "dev" of organization "my-org"`)
+			})
+
+		})
+
+		Convey("When an app is resolved by a name containing a comma", func() {
+
+			_, err := cc.ResolveApp("my-org", "dev", "my-app,other-app")
+
+			Convey("Then the name is rejected instead of being searched as two names", func() {
+				So(IsNotFound(err), ShouldEqual, false)
+				So(IsAmbiguous(err), ShouldEqual, false)
+				So(err.Error(), ShouldEqual, `app name "my-app,other-app" must not contain a comma`)
+			})
+
+		})
+
+		Convey("When an app is resolved whose name is used in several spaces of the org", func() {
+
+			_, err := cc.ResolveApp("my-org", "", "my-app")
+
+			Convey("Then an ambiguous error lists the matches", func() {
+				So(IsAmbiguous(err), ShouldEqual, true)
+				So(IsNotFound(err), ShouldEqual, false)
+				So(err.Error(), ShouldEqual, `app "my-app" is ambiguous in organization "my-org", matches app-guid, other-app-guid`)
+			})
+
+		})
+
+		Convey("When a space is resolved without org whose name is used in several orgs", func() {
+
+			_, err := cc.ResolveSpace("", "dev")
+
+			Convey("Then an ambiguous error is returned", func() {
+				So(IsAmbiguous(err), ShouldEqual, true)
+				So(err.Error(), ShouldEqual, `space "dev" is ambiguous, matches space-guid, other-space-guid`)
+			})
+
+		})
+
+		Convey("When an app is resolved without org and space with the target of the cf CLI", func() {
+
+			cc.TargetOrganizationName = "my-org"
+			cc.TargetSpaceName = "dev"
+			app, err := cc.ResolveApp("", "", "my-app")
+
+			Convey("Then the app is searched in the targeted space", func() {
+				So(err, ShouldEqual, nil)
+				So(app.GUID, ShouldEqual, "app-guid")
+			})
+
+		})
+
+		Convey("When a space is resolved without org with the target of the cf CLI", func() {
+
+			cc.TargetOrganizationName = "my-org"
+			space, err := cc.ResolveSpace("", "dev")
+
+			Convey("Then the space is searched in the targeted org", func() {
+				So(err, ShouldEqual, nil)
+				So(space.GUID, ShouldEqual, "space-guid")
+			})
+
+		})
+
+		Convey("When a space of an unknown org is resolved", func() {
+
+			_, err := cc.ResolveSpace("unknown-org", "dev")
+
+			Convey("Then the org is reported as not found", func() {
+				So(IsNotFound(err), ShouldEqual, true)
+				So(err.Error(), ShouldEqual, `organization "unknown-org" not found`)
+			})
+
+		})
+
+	})
+
+}
@@ -1,10 +1,13 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
 	//"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 )
 
 // ResourceList - List of generic resources
@@ -24,44 +27,106 @@ type Resource struct {
 
 // GetResourceList returns a map of Resources
 func (c *CloudController) GetResourceList(apiPath string) (*map[string]Resource, error) {
+	return c.GetResourceListContext(context.Background(), apiPath)
+}
+
+// GetResourceListContext is like GetResourceList but uses the given context.
+// Once the first page reports the total number of pages the remaining pages
+// are fetched concurrently by up to Config.MaxConcurrency workers. Paging
+// stops as soon as the context is cancelled or a page fails.
+func (c *CloudController) GetResourceListContext(ctx context.Context, apiPath string) (*map[string]Resource, error) {
+
+	first, err := c.getResourceListPage(ctx, apiPath, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceList := make(map[string]Resource)
+	for _, value := range first.Resources {
+		resourceList[value.Metadata.GUID] = value
+	}
+
+	if first.TotalPages <= 1 {
+		return &resourceList, nil
+	}
+
+	pageCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var pageErr error
+	pages := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < c.maxConcurrency() && w < first.TotalPages-1; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pages {
+				list, err := c.getResourceListPage(pageCtx, apiPath, page)
+
+				mutex.Lock()
+				if err != nil && pageErr == nil {
+					pageErr = err
+					cancel()
+				}
+				if err == nil {
+					for _, value := range list.Resources {
+						resourceList[value.Metadata.GUID] = value
+					}
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for page := 2; page <= first.TotalPages && pageCtx.Err() == nil; page++ {
+		select {
+		case pages <- page:
+		case <-pageCtx.Done():
+		}
+	}
+	close(pages)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if pageErr != nil {
+		return nil, pageErr
+	}
+
+	return &resourceList, nil
+}
+
+// getResourceListPage returns a single page of a v2 resource list.
+func (c *CloudController) getResourceListPage(ctx context.Context, apiPath string, page int) (*ResourceList, error) {
 	apiURLRelative := &url.URL{Path: apiPath}
 	apiURL := c.APIUrl.ResolveReference(apiURLRelative)
 
-	req, err := http.NewRequest("GET", apiURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken.AccessToken)
 
 	q := req.URL.Query()
 	q.Add("results-per-page", "100")
+	q.Add("page", strconv.Itoa(page))
 	req.URL.RawQuery = q.Encode()
 
-	var i ResourceList
-	resourceList := make(map[string]Resource)
-	hasNext := true
-
-	for hasNext {
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		err = json.NewDecoder(resp.Body).Decode(&i)
-
-		for _, value := range i.Resources {
-			resourceList[value.Metadata.GUID] = value
-		}
+	resp, err := c.doAuthenticated(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-		hasNext = !(i.NextURL == "")
-		if hasNext {
-			apiURL, _ = url.Parse(c.APIUrl.String() + i.NextURL)
-			req.URL = apiURL
-			i.NextURL = ""
-		}
+	var i ResourceList
+	err = json.NewDecoder(resp.Body).Decode(&i)
+	if err != nil {
+		return nil, err
 	}
 
-	return &resourceList, err
+	return &i, nil
 }
@@ -0,0 +1,126 @@
+package cloudfoundry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestResourceList(t *testing.T) {
+
+	pagedHandler := func(totalPages int, onPage func(page int)) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := 1
+			fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+			if onPage != nil {
+				onPage(page)
+			}
+
+			nextURL := ""
+			if page < totalPages {
+				nextURL = fmt.Sprintf("/v2/stacks?page=%d&results-per-page=100", page+1)
+			}
+			fmt.Fprintf(w, `{"total_results": %d, "total_pages": %d, "next_url": "%s", "resources": [{"metadata": {"guid": "stack-%d"}, "entity": {"name": "stack-%d"}}]}`,
+				totalPages, totalPages, nextURL, page, page)
+		})
+	}
+
+	Convey("Given a v2 resource list with several pages", t, func() {
+
+		Convey("When the resource list is requested", func() {
+
+			Convey("Then the resources of all pages are returned", func() {
+				var mutex sync.Mutex
+				requestedPages := make(map[int]int)
+				httpClient, teardown := testingHTTPClient(pagedHandler(12, func(page int) {
+					mutex.Lock()
+					requestedPages[page]++
+					mutex.Unlock()
+				}))
+				defer teardown()
+
+				cc := testingCloudController(httpClient, CloudControllerConfig{MaxConcurrency: 3})
+
+				resources, err := cc.GetResourceList("/v2/stacks")
+				So(err, ShouldEqual, nil)
+				So(len(*resources), ShouldEqual, 12)
+				So((*resources)["stack-12"].Metadata.GUID, ShouldEqual, "stack-12")
+				So(len(requestedPages), ShouldEqual, 12)
+				for _, count := range requestedPages {
+					So(count, ShouldEqual, 1)
+				}
+			})
+
+		})
+
+		Convey("When the context is cancelled while paging", func() {
+
+			Convey("Then paging stops with the context error", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				requestedPages := 0
+				httpClient, teardown := testingHTTPClient(pagedHandler(5, func(page int) {
+					requestedPages++
+					if page == 2 {
+						cancel()
+					}
+				}))
+				defer teardown()
+
+				cc := testingCloudController(httpClient, CloudControllerConfig{MaxConcurrency: 1})
+
+				_, err := cc.GetResourceListContext(ctx, "/v2/stacks")
+				So(errors.Is(err, context.Canceled), ShouldEqual, true)
+				So(requestedPages, ShouldBeLessThan, 5)
+			})
+
+		})
+
+	})
+
+	Convey("Given a v3 list filtered by more guids than fit into one request", t, func() {
+
+		var requests []int
+		httpClient, teardown := testingHTTPClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			guids := strings.Split(r.URL.Query().Get("guids"), ",")
+			requests = append(requests, len(guids))
+
+			resources := []string{`{"guid": "shared-guid", "name": "shared"}`}
+			for _, guid := range guids {
+				resources = append(resources, fmt.Sprintf(`{"guid": "%s", "name": "%s"}`, guid, guid))
+			}
+			fmt.Fprintf(w, `{"pagination": {"total_results": %d, "total_pages": 1}, "resources": [%s]}`, len(resources), strings.Join(resources, ","))
+		}))
+		defer teardown()
+
+		guids := make([]string, 0, 250)
+		for i := 0; i < 250; i++ {
+			guids = append(guids, fmt.Sprintf("domain-%03d", i))
+		}
+
+		Convey("When the list is requested", func() {
+
+			cc := testingCloudController(httpClient, CloudControllerConfig{MaxConcurrency: 1})
+			domains, err := cc.QueryV3DomainsContext(context.Background(), (&v3.ListQuery{}).Filter("guids", guids...))
+
+			Convey("Then the guids are split over several requests", func() {
+				So(err, ShouldEqual, nil)
+				So(requests, ShouldResemble, []int{100, 100, 50})
+			})
+
+			Convey("Then every resource is returned once", func() {
+				So(len(domains), ShouldEqual, 251)
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,82 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+)
+
+// GetV3AppRoutes - Loads the routes of the app together with their
+// destinations and domains.
+func (c *CloudController) GetV3AppRoutes(appGUID string) ([]*v3.Route, error) {
+	return c.GetV3AppRoutesContext(context.Background(), appGUID)
+}
+
+// GetV3AppRoutesContext is like GetV3AppRoutes but uses the given context.
+func (c *CloudController) GetV3AppRoutesContext(ctx context.Context, appGUID string) ([]*v3.Route, error) {
+	return c.queryV3Routes(ctx, "/v3/apps/"+appGUID+"/routes", &v3.ListQuery{PerPage: v3MaxPerPage})
+}
+
+// QueryV3RoutesContext loads the routes matching the query, e.g. all routes
+// of some spaces, together with their destinations and domains.
+func (c *CloudController) QueryV3RoutesContext(ctx context.Context, query *v3.ListQuery) ([]*v3.Route, error) {
+
+	err := checkLabelSelector(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.queryV3Routes(ctx, "/v3/routes", query)
+}
+
+// queryV3Routes lists the routes at the path and adds them to V3RouteMap.
+// Missing destinations and the domains of the routes are loaded as well.
+func (c *CloudController) queryV3Routes(ctx context.Context, path string, query *v3.ListQuery) ([]*v3.Route, error) {
+
+	routes, err := listV3[v3.Route](ctx, c, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	domainGUIDs := make(map[string]bool)
+	for _, r := range routes {
+		if r.Destinations == nil {
+			destinations, err := c.GetV3RouteDestinationsContext(ctx, r.GUID)
+			if err != nil {
+				return nil, err
+			}
+			r.Destinations = destinations
+		}
+
+		if r.DomainGUID() != "" {
+			domainGUIDs[r.DomainGUID()] = true
+		}
+	}
+
+	storeV3(c, &c.V3RouteMap, routes, func(r *v3.Route) string { return r.GUID })
+
+	if len(domainGUIDs) > 0 {
+		_, err = c.QueryV3DomainsContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(domainGUIDs)...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return routes, nil
+}
+
+// GetV3RouteDestinationsContext loads the destinations of the route.
+func (c *CloudController) GetV3RouteDestinationsContext(ctx context.Context, routeGUID string) ([]*v3.RouteDestination, error) {
+
+	var destinations v3.RouteDestinations
+	err := c.GetJSON(ctx, "/v3/routes/"+routeGUID+"/destinations", &destinations)
+	if err != nil {
+		return nil, err
+	}
+
+	return destinations.Destinations, nil
+}
+
+// V3Routes returns the loaded routes in no particular order.
+func (c *CloudController) V3Routes() []*v3.Route {
+	return valuesV3(c, &c.V3RouteMap)
+}
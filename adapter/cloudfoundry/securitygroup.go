@@ -0,0 +1,48 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// GetV3SecurityGroups - Loads all security groups visible to the user, the
+// global defaults as well as the ones bound to spaces.
+func (c *CloudController) GetV3SecurityGroups() error {
+	return c.GetV3SecurityGroupsContext(context.Background())
+}
+
+// GetV3SecurityGroupsContext is like GetV3SecurityGroups but uses the given
+// context.
+func (c *CloudController) GetV3SecurityGroupsContext(ctx context.Context) error {
+
+	_, err := c.QueryV3SecurityGroupsContext(ctx, &v3.ListQuery{PerPage: v3MaxPerPage})
+	return err
+}
+
+// QueryV3SecurityGroupsContext loads the security groups matching the query,
+// e.g. running_space_guids, and adds them to V3SecurityGroupMap.
+func (c *CloudController) QueryV3SecurityGroupsContext(ctx context.Context, query *v3.ListQuery) ([]*v3.SecurityGroup, error) {
+	return queryV3(ctx, c, "/v3/security_groups", query, &c.V3SecurityGroupMap, func(s *v3.SecurityGroup) string { return s.GUID })
+}
+
+// SecurityGroupsOf returns the loaded security groups which apply to the
+// apps of the space in the lifecycle, sorted by name.
+func (c *CloudController) SecurityGroupsOf(spaceGUID string, lifecycle string) []*v3.SecurityGroup {
+
+	var result []*v3.SecurityGroup
+
+	c.mapMutex.Lock()
+	if c.V3SecurityGroupMap != nil {
+		for _, g := range *c.V3SecurityGroupMap {
+			if g.AppliesTo(spaceGUID, lifecycle) {
+				result = append(result, g)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
@@ -0,0 +1,175 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+)
+
+// GetV3AppServiceBindings - Loads the service bindings of the app together
+// with the bound service instances and their plans, offerings and brokers.
+// Credentials are never requested.
+func (c *CloudController) GetV3AppServiceBindings(appGUID string) ([]*v3.ServiceCredentialBinding, error) {
+	return c.GetV3AppServiceBindingsContext(context.Background(), appGUID)
+}
+
+// GetV3AppServiceBindingsContext is like GetV3AppServiceBindings but uses the
+// given context.
+func (c *CloudController) GetV3AppServiceBindingsContext(ctx context.Context, appGUID string) ([]*v3.ServiceCredentialBinding, error) {
+	return c.GetV3AppsServiceBindingsContext(ctx, appGUID)
+}
+
+// GetV3AppsServiceBindingsContext is like GetV3AppServiceBindingsContext but
+// loads the bindings of several apps at once.
+func (c *CloudController) GetV3AppsServiceBindingsContext(ctx context.Context, appGUIDs ...string) ([]*v3.ServiceCredentialBinding, error) {
+
+	if len(appGUIDs) == 0 {
+		return nil, nil
+	}
+
+	query := (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("app_guids", appGUIDs...).Filter("type", "app")
+	bindings, err := c.QueryV3ServiceCredentialBindingsContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceGUIDs := make(map[string]bool)
+	for _, b := range bindings {
+		if b.ServiceInstanceGUID() != "" {
+			instanceGUIDs[b.ServiceInstanceGUID()] = true
+		}
+	}
+
+	if len(instanceGUIDs) > 0 {
+		_, err = c.loadV3ServiceInstances(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(instanceGUIDs)...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bindings, nil
+}
+
+// GetV3SpaceServiceInstancesContext loads all service instances of the
+// space, bound or not, with their plans, offerings and brokers.
+func (c *CloudController) GetV3SpaceServiceInstancesContext(ctx context.Context, spaceGUID string) ([]*v3.ServiceInstance, error) {
+	return c.loadV3ServiceInstances(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("space_guids", spaceGUID))
+}
+
+// loadV3ServiceInstances loads the service instances matching the query and
+// the plans, offerings and brokers of the managed ones.
+func (c *CloudController) loadV3ServiceInstances(ctx context.Context, query *v3.ListQuery) ([]*v3.ServiceInstance, error) {
+
+	instances, err := c.QueryV3ServiceInstancesContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, c.loadV3ServicePlans(ctx, instances)
+}
+
+// loadV3ServicePlans loads plans, offerings and brokers of the instances.
+func (c *CloudController) loadV3ServicePlans(ctx context.Context, instances []*v3.ServiceInstance) error {
+
+	planGUIDs := make(map[string]bool)
+	for _, i := range instances {
+		if i.ServicePlanGUID() != "" {
+			planGUIDs[i.ServicePlanGUID()] = true
+		}
+	}
+	if len(planGUIDs) == 0 {
+		return nil
+	}
+
+	plans, err := c.QueryV3ServicePlansContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(planGUIDs)...))
+	if err != nil {
+		return err
+	}
+
+	offeringGUIDs := make(map[string]bool)
+	for _, p := range plans {
+		if p.ServiceOfferingGUID() != "" {
+			offeringGUIDs[p.ServiceOfferingGUID()] = true
+		}
+	}
+	if len(offeringGUIDs) == 0 {
+		return nil
+	}
+
+	offerings, err := c.QueryV3ServiceOfferingsContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(offeringGUIDs)...))
+	if err != nil {
+		return err
+	}
+
+	brokerGUIDs := make(map[string]bool)
+	for _, o := range offerings {
+		if o.ServiceBrokerGUID() != "" {
+			brokerGUIDs[o.ServiceBrokerGUID()] = true
+		}
+	}
+	if len(brokerGUIDs) == 0 {
+		return nil
+	}
+
+	_, err = c.QueryV3ServiceBrokersContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(brokerGUIDs)...))
+	return err
+}
+
+// QueryV3ServiceCredentialBindingsContext loads the service credential
+// bindings matching the query and adds them to V3ServiceCredentialBindingMap.
+func (c *CloudController) QueryV3ServiceCredentialBindingsContext(ctx context.Context, query *v3.ListQuery) ([]*v3.ServiceCredentialBinding, error) {
+	return queryV3(ctx, c, "/v3/service_credential_bindings", query, &c.V3ServiceCredentialBindingMap, func(s *v3.ServiceCredentialBinding) string { return s.GUID })
+}
+
+// QueryV3ServiceInstancesContext loads the managed and user-provided service
+// instances matching the query and adds them to V3ServiceInstanceMap.
+func (c *CloudController) QueryV3ServiceInstancesContext(ctx context.Context, query *v3.ListQuery) ([]*v3.ServiceInstance, error) {
+	return queryV3(ctx, c, "/v3/service_instances", query, &c.V3ServiceInstanceMap, func(s *v3.ServiceInstance) string { return s.GUID })
+}
+
+// QueryV3ServicePlansContext loads the service plans matching the query and
+// adds them to V3ServicePlanMap.
+func (c *CloudController) QueryV3ServicePlansContext(ctx context.Context, query *v3.ListQuery) ([]*v3.ServicePlan, error) {
+	return queryV3(ctx, c, "/v3/service_plans", query, &c.V3ServicePlanMap, func(s *v3.ServicePlan) string { return s.GUID })
+}
+
+// QueryV3ServiceOfferingsContext loads the service offerings matching the
+// query and adds them to V3ServiceOfferingMap.
+func (c *CloudController) QueryV3ServiceOfferingsContext(ctx context.Context, query *v3.ListQuery) ([]*v3.ServiceOffering, error) {
+	return queryV3(ctx, c, "/v3/service_offerings", query, &c.V3ServiceOfferingMap, func(s *v3.ServiceOffering) string { return s.GUID })
+}
+
+// QueryV3ServiceBrokersContext loads the service brokers matching the query
+// and adds them to V3ServiceBrokerMap.
+func (c *CloudController) QueryV3ServiceBrokersContext(ctx context.Context, query *v3.ListQuery) ([]*v3.ServiceBroker, error) {
+	return queryV3(ctx, c, "/v3/service_brokers", query, &c.V3ServiceBrokerMap, func(s *v3.ServiceBroker) string { return s.GUID })
+}
+
+// V3ServiceCredentialBindings returns the loaded service credential bindings in no particular order.
+func (c *CloudController) V3ServiceCredentialBindings() []*v3.ServiceCredentialBinding {
+	return valuesV3(c, &c.V3ServiceCredentialBindingMap)
+}
+
+// V3ServiceInstances returns the loaded service instances in no particular order.
+func (c *CloudController) V3ServiceInstances() []*v3.ServiceInstance {
+	return valuesV3(c, &c.V3ServiceInstanceMap)
+}
+
+// V3ServiceInstance returns the loaded service instance with the guid or nil.
+func (c *CloudController) V3ServiceInstance(guid string) *v3.ServiceInstance {
+	return lookupV3(c, &c.V3ServiceInstanceMap, guid)
+}
+
+// V3ServicePlan returns the loaded service plan with the guid or nil.
+func (c *CloudController) V3ServicePlan(guid string) *v3.ServicePlan {
+	return lookupV3(c, &c.V3ServicePlanMap, guid)
+}
+
+// V3ServiceOffering returns the loaded service offering with the guid or nil.
+func (c *CloudController) V3ServiceOffering(guid string) *v3.ServiceOffering {
+	return lookupV3(c, &c.V3ServiceOfferingMap, guid)
+}
+
+// V3ServiceBroker returns the loaded service broker with the guid or nil.
+func (c *CloudController) V3ServiceBroker(guid string) *v3.ServiceBroker {
+	return lookupV3(c, &c.V3ServiceBrokerMap, guid)
+}
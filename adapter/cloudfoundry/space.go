@@ -1,8 +1,11 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
 )
 
 // SpaceEntity
@@ -38,8 +41,16 @@ type SpaceInfo struct {
 
 // GetSpaces
 func (c *CloudController) GetSpaces() error {
+	return c.GetSpacesContext(context.Background())
+}
+
+// GetSpacesContext is like GetSpaces but uses the given context.
+func (c *CloudController) GetSpacesContext(ctx context.Context) error {
 
-	spaceResources, err := c.GetResourceList("/v2/spaces")
+	spaceResources, err := c.GetResourceListContext(ctx, "/v2/spaces")
+	if err != nil {
+		return err
+	}
 
 	resultMap := make(map[string]*SpaceInfo)
 
@@ -66,3 +77,96 @@ func (c *CloudController) GetSpaces() error {
 	return err
 
 }
+
+// GetV3Spaces - Loads all spaces from the v3 API.
+func (c *CloudController) GetV3Spaces() error {
+	return c.GetV3SpacesContext(context.Background())
+}
+
+// GetV3SpacesContext is like GetV3Spaces but uses the given context.
+func (c *CloudController) GetV3SpacesContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/spaces", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3SpaceMap, func(s *v3.Space) string { return s.GUID })
+	return err
+}
+
+// QueryV3SpacesContext loads the spaces matching the query, e.g. a
+// label_selector, and adds them to V3SpaceMap.
+func (c *CloudController) QueryV3SpacesContext(ctx context.Context, query *v3.ListQuery) ([]*v3.Space, error) {
+	return queryV3(ctx, c, "/v3/spaces", query, &c.V3SpaceMap, func(s *v3.Space) string { return s.GUID })
+}
+
+// SelectV3Spaces returns the already loaded spaces whose labels match
+// the label selector, sorted by name.
+func (c *CloudController) SelectV3Spaces(selector string) ([]*v3.Space, error) {
+
+	labelSelector, err := v3.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*v3.Space
+	c.mapMutex.Lock()
+	if c.V3SpaceMap != nil {
+		for _, s := range *c.V3SpaceMap {
+			if labelSelector.Matches(s.Metadata.GetLabels()) {
+				result = append(result, s)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetV3AppSpacesContext loads the spaces of the apps and the organizations
+// of these spaces, both sorted by name.
+func (c *CloudController) GetV3AppSpacesContext(ctx context.Context, apps []*v3.App) ([]*v3.Space, []*v3.Organization, error) {
+
+	spaceGUIDs := make(map[string]bool)
+	for _, app := range apps {
+		if app.SpaceGUID() != "" {
+			spaceGUIDs[app.SpaceGUID()] = true
+		}
+	}
+
+	var spaces []*v3.Space
+	if len(spaceGUIDs) > 0 {
+		var err error
+		spaces, err = c.QueryV3SpacesContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(spaceGUIDs)...))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.Slice(spaces, func(i, j int) bool { return spaces[i].Name < spaces[j].Name })
+
+	orgGUIDs := make(map[string]bool)
+	for _, space := range spaces {
+		if space.OrganizationGUID() != "" {
+			orgGUIDs[space.OrganizationGUID()] = true
+		}
+	}
+
+	var orgs []*v3.Organization
+	if len(orgGUIDs) > 0 {
+		var err error
+		orgs, err = c.QueryV3OrganizationsContext(ctx, (&v3.ListQuery{PerPage: v3MaxPerPage}).Filter("guids", keys(orgGUIDs)...))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+
+	return spaces, orgs, nil
+}
+
+// V3Spaces returns the loaded spaces in no particular order.
+func (c *CloudController) V3Spaces() []*v3.Space {
+	return valuesV3(c, &c.V3SpaceMap)
+}
+
+// V3Space returns the loaded space with the guid or nil.
+func (c *CloudController) V3Space(guid string) *v3.Space {
+	return lookupV3(c, &c.V3SpaceMap, guid)
+}
@@ -1,7 +1,9 @@
 package cloudfoundry
 
 import (
+	"context"
 	"encoding/json"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
 	//"fmt"
 	// "errors"
 	// "net/http"
@@ -22,8 +24,16 @@ type StackInfo struct {
 
 // GetStacks - Loads infos about all stacks
 func (c *CloudController) GetStacks() error {
+	return c.GetStacksContext(context.Background())
+}
+
+// GetStacksContext is like GetStacks but uses the given context.
+func (c *CloudController) GetStacksContext(ctx context.Context) error {
 
-	stackResources, err := c.GetResourceList("/v2/stacks")
+	stackResources, err := c.GetResourceListContext(ctx, "/v2/stacks")
+	if err != nil {
+		return err
+	}
 	resultMap := make(map[string]*StackInfo)
 
 	for _, value := range *stackResources {
@@ -46,3 +56,20 @@ func (c *CloudController) GetStacks() error {
 	return err
 
 }
+
+// GetV3Stacks - Loads all stacks from the v3 API.
+func (c *CloudController) GetV3Stacks() error {
+	return c.GetV3StacksContext(context.Background())
+}
+
+// GetV3StacksContext is like GetV3Stacks but uses the given context.
+func (c *CloudController) GetV3StacksContext(ctx context.Context) error {
+
+	_, err := queryV3(ctx, c, "/v3/stacks", &v3.ListQuery{PerPage: v3MaxPerPage}, &c.V3StackMap, func(s *v3.Stack) string { return s.GUID })
+	return err
+}
+
+// V3Stacks returns the loaded stacks in no particular order.
+func (c *CloudController) V3Stacks() []*v3.Stack {
+	return valuesV3(c, &c.V3StackMap)
+}
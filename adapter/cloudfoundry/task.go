@@ -0,0 +1,124 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// DefaultRecentTasks is the number of tasks loaded by GetV3AppTasks.
+const DefaultRecentTasks = 10
+
+// GetV3AppSidecars - Loads the sidecars of the app and adds them to
+// V3SidecarMap.
+func (c *CloudController) GetV3AppSidecars(appGUID string) ([]*v3.Sidecar, error) {
+	return c.GetV3AppSidecarsContext(context.Background(), appGUID)
+}
+
+// GetV3AppSidecarsContext is like GetV3AppSidecars but uses the given
+// context.
+func (c *CloudController) GetV3AppSidecarsContext(ctx context.Context, appGUID string) ([]*v3.Sidecar, error) {
+
+	sidecars, err := listV3[v3.Sidecar](ctx, c, "/v3/apps/"+appGUID+"/sidecars", &v3.ListQuery{PerPage: v3MaxPerPage})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range sidecars {
+		if s.AppGUID() == "" {
+			s.Relationships = &v3.ProcessRelationships{App: appRelationship(appGUID)}
+		}
+	}
+
+	storeV3(c, &c.V3SidecarMap, sidecars, func(s *v3.Sidecar) string { return s.GUID })
+
+	return sidecars, nil
+}
+
+// GetV3AppTasks - Loads the most recent tasks of the app and adds them to
+// V3TaskMap. A limit of zero means DefaultRecentTasks.
+func (c *CloudController) GetV3AppTasks(appGUID string, limit int) ([]*v3.Task, error) {
+	return c.GetV3AppTasksContext(context.Background(), appGUID, limit)
+}
+
+// GetV3AppTasksContext is like GetV3AppTasks but uses the given context.
+func (c *CloudController) GetV3AppTasksContext(ctx context.Context, appGUID string, limit int) ([]*v3.Task, error) {
+
+	if limit <= 0 {
+		limit = DefaultRecentTasks
+	}
+
+	var tasks []*v3.Task
+	it := v3.NewListIterator(c, "/v3/apps/"+appGUID+"/tasks", &v3.ListQuery{PerPage: limit, OrderBy: "-created_at"})
+	for len(tasks) < limit && it.Next(ctx) {
+		t := new(v3.Task)
+		err := it.Decode(t)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.AppGUID() == "" {
+			t.Relationships = &v3.ProcessRelationships{App: appRelationship(appGUID)}
+		}
+
+		tasks = append(tasks, t)
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+
+	storeV3(c, &c.V3TaskMap, tasks, func(t *v3.Task) string { return t.GUID })
+
+	return tasks, nil
+}
+
+// SidecarsOf returns the loaded sidecars of the app sorted by name.
+func (c *CloudController) SidecarsOf(appGUID string) []*v3.Sidecar {
+
+	var result []*v3.Sidecar
+
+	c.mapMutex.Lock()
+	if c.V3SidecarMap != nil {
+		for _, s := range *c.V3SidecarMap {
+			if s.AppGUID() == appGUID {
+				result = append(result, s)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
+
+// TasksOf returns the loaded tasks of the app, newest first.
+func (c *CloudController) TasksOf(appGUID string) []*v3.Task {
+
+	var result []*v3.Task
+
+	c.mapMutex.Lock()
+	if c.V3TaskMap != nil {
+		for _, t := range *c.V3TaskMap {
+			if t.AppGUID() == appGUID {
+				result = append(result, t)
+			}
+		}
+	}
+	c.mapMutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt != result[j].CreatedAt {
+			return result[i].CreatedAt > result[j].CreatedAt
+		}
+		return result[i].SequenceID > result[j].SequenceID
+	})
+
+	return result
+}
+
+// appRelationship returns a relationship to the app for resources which the
+// cc API links to their app only by link.
+func appRelationship(appGUID string) *v3.Relationship {
+	return &v3.Relationship{Data: &v3.RelationshipData{GUID: appGUID}}
+}
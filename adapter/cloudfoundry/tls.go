@@ -0,0 +1,79 @@
+package cloudfoundry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// newTLSConfig returns the TLS configuration for connections to the cc API
+// and the UAA. Certificates are verified against the system pool plus the
+// certificates found at CACertPath unless SkipSSLValidation is set.
+func newTLSConfig(c *CloudControllerConfig) (*tls.Config, error) {
+
+	if c.SkipSSLValidation {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	if c.CACertPath == "" {
+		return &tls.Config{}, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	err = appendCACerts(pool, c.CACertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// appendCACerts adds the PEM encoded certificates in the given file, or in
+// all files of the given directory, to the pool.
+func appendCACerts(pool *x509.CertPool, path string) error {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return err
+		}
+
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	found := false
+	for _, file := range files {
+		pem, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if pool.AppendCertsFromPEM(pem) {
+			found = true
+		}
+	}
+
+	if !found {
+		return errors.New("no PEM encoded certificates found in " + path)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package cloudfoundry
+
+import (
+	"encoding/pem"
+	. "github.com/smartystreets/goconvey/convey"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCloudControllerTLS(t *testing.T) {
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"api_version": "2.133.0"}`))
+	})
+
+	Convey("Given a cc API with a certificate from an unknown CA", t, func() {
+
+		server := httptest.NewTLSServer(h)
+		defer server.Close()
+
+		Convey("When TLS validation is not configured", func() {
+
+			Convey("Then the certificate is rejected", func() {
+				cc, err := NewCloudController(CloudControllerConfig{Username: "u", Password: "p", APIURLString: server.URL})
+				So(err, ShouldEqual, nil)
+
+				_, err = cc.GetV2Info()
+				So(err, ShouldNotEqual, nil)
+			})
+
+		})
+
+		Convey("When SSL validation is skipped", func() {
+
+			Convey("Then the cc API can be reached", func() {
+				cc, err := NewCloudController(CloudControllerConfig{Username: "u", Password: "p", APIURLString: server.URL, SkipSSLValidation: true})
+				So(err, ShouldEqual, nil)
+
+				info, err := cc.GetV2Info()
+				So(err, ShouldEqual, nil)
+				So(info.APIVersion, ShouldEqual, "2.133.0")
+			})
+
+		})
+
+		Convey("When the CA certificate directory contains the certificate of the cc API", func() {
+
+			dir, _ := ioutil.TempDir("", "cacerts")
+			defer os.RemoveAll(dir)
+			certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			ioutil.WriteFile(filepath.Join(dir, "ca.pem"), certPEM, 0600)
+
+			Convey("Then the cc API can be reached with TLS validation", func() {
+				cc, err := NewCloudController(CloudControllerConfig{Username: "u", Password: "p", APIURLString: server.URL, CACertPath: dir})
+				So(err, ShouldEqual, nil)
+
+				info, err := cc.GetV2Info()
+				So(err, ShouldEqual, nil)
+				So(info.APIVersion, ShouldEqual, "2.133.0")
+			})
+
+		})
+
+		Convey("When the CA certificate file contains no certificate", func() {
+
+			file, _ := ioutil.TempFile("", "cacert")
+			file.WriteString("no certificate")
+			file.Close()
+			defer os.Remove(file.Name())
+
+			Convey("Then an error message indicates the missing certificates", func() {
+				_, err := NewCloudController(CloudControllerConfig{Username: "u", Password: "p", APIURLString: server.URL, CACertPath: file.Name()})
+
+				So(err.Error(), ShouldEqual, "no PEM encoded certificates found in "+file.Name())
+			})
+
+		})
+
+	})
+
+}
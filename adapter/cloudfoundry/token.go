@@ -0,0 +1,278 @@
+package cloudfoundry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenExpiryMargin is subtracted from the token lifetime so that a token is
+// refreshed shortly before the UAA actually rejects it.
+const tokenExpiryMargin = 30 * time.Second
+
+// requestToken posts the given grant parameters to the UAA token endpoint and
+// stores the returned access token together with its expiry.
+func (c *CloudController) requestToken(ctx context.Context, parameters url.Values) error {
+
+	if c.TokenURL == nil {
+		return errors.New("token endpoint is unknown, login first")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL.String(), strings.NewReader(parameters.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.clientCredentials())
+
+	resp, err := c.execute(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = checkResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	var ati AccessTokenInfo
+	err = json.NewDecoder(resp.Body).Decode(&ati)
+	if err != nil {
+		return err
+	}
+
+	c.AccessToken = &ati
+	c.tokenExpiresAt = time.Now().Add(time.Duration(ati.ExpiresIn) * time.Second)
+	return nil
+}
+
+// setAccessToken stores a pre-issued access token. The expiry is taken from
+// the exp claim when the token is a JWT.
+func (c *CloudController) setAccessToken(accessToken string, refreshToken string) {
+
+	accessToken = strings.TrimSpace(accessToken)
+	if len(accessToken) > 7 && strings.EqualFold(accessToken[:7], "bearer ") {
+		accessToken = accessToken[7:]
+	}
+
+	c.AccessToken = &AccessTokenInfo{
+		AccessToken:  accessToken,
+		TokenType:    "bearer",
+		RefreshToken: refreshToken,
+	}
+
+	c.tokenExpiresAt = time.Time{}
+	expiresAt, err := tokenExpiry(accessToken)
+	if err == nil {
+		c.tokenExpiresAt = expiresAt
+	}
+}
+
+// tokenClaims contains the JWT claims of an access token used by cloudpaint.
+type tokenClaims struct {
+	ExpiresAt int64 `json:"exp"`
+}
+
+// tokenExpiry returns the expiry time contained in the exp claim of the JWT.
+func tokenExpiry(token string) (time.Time, error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("access token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var claims tokenClaims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if claims.ExpiresAt == 0 {
+		return time.Time{}, errors.New("access token does not contain an expiry")
+	}
+
+	return time.Unix(claims.ExpiresAt, 0), nil
+}
+
+// RefreshAccessToken uses the refresh token of the current access token to
+// retrieve a new access token from the UAA.
+func (c *CloudController) RefreshAccessToken() error {
+	return c.RefreshAccessTokenContext(context.Background())
+}
+
+// RefreshAccessTokenContext is like RefreshAccessToken but uses the given
+// context.
+func (c *CloudController) RefreshAccessTokenContext(ctx context.Context) error {
+
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	return c.refreshAccessToken(ctx)
+}
+
+func (c *CloudController) refreshAccessToken(ctx context.Context) error {
+
+	if c.Config.AuthType == AuthTypeClientCredentials {
+		return c.requestToken(ctx, c.grantParameters())
+	}
+
+	if c.AccessToken == nil || c.AccessToken.RefreshToken == "" {
+		return errors.New("no refresh token available")
+	}
+
+	parameters := url.Values{}
+	parameters.Set("refresh_token", c.AccessToken.RefreshToken)
+	parameters.Set("grant_type", "refresh_token")
+
+	return c.requestToken(ctx, parameters)
+}
+
+// canRefresh reports whether a new access token can be obtained without user
+// interaction.
+func (c *CloudController) canRefresh() bool {
+
+	if c.Config.AuthType == AuthTypeClientCredentials {
+		return true
+	}
+
+	return c.AccessToken != nil && c.AccessToken.RefreshToken != ""
+}
+
+// grantParameters returns the token request parameters for the configured
+// auth type.
+func (c *CloudController) grantParameters() url.Values {
+
+	parameters := url.Values{}
+
+	switch c.Config.AuthType {
+	case AuthTypeClientCredentials:
+		parameters.Set("grant_type", "client_credentials")
+	case AuthTypePasscode:
+		parameters.Set("passcode", c.Config.Passcode)
+		parameters.Set("grant_type", "password")
+	default:
+		parameters.Set("username", c.Config.Username)
+		parameters.Set("password", c.Config.Password)
+		parameters.Set("scope", "")
+		parameters.Set("grant_type", "password")
+	}
+
+	return parameters
+}
+
+// clientCredentials returns the UAA client used for token requests. All auth
+// types except client_credentials use the public "cf" client like the cf CLI.
+func (c *CloudController) clientCredentials() (string, string) {
+
+	if c.Config.AuthType == AuthTypeClientCredentials {
+		return c.Config.ClientID, c.Config.ClientSecret
+	}
+
+	return "cf", ""
+}
+
+// tokenExpired reports whether the current access token is expired or about
+// to expire. Tokens without a known lifetime are never considered expired.
+func (c *CloudController) tokenExpired() bool {
+
+	if c.tokenExpiresAt.IsZero() {
+		return false
+	}
+
+	return time.Now().Add(tokenExpiryMargin).After(c.tokenExpiresAt)
+}
+
+// currentAccessToken returns a valid access token, refreshing it first when
+// it is expired.
+func (c *CloudController) currentAccessToken(ctx context.Context) (string, error) {
+
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if c.AccessToken == nil {
+		return "", errors.New("not logged in")
+	}
+
+	if c.tokenExpired() && c.canRefresh() {
+		err := c.refreshAccessToken(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return c.AccessToken.AccessToken, nil
+}
+
+// doAuthenticated sends the request with the current access token. When the
+// cc API answers with 401 the token is refreshed and the request is retried
+// once. Unsuccessful responses are returned as *APIError.
+func (c *CloudController) doAuthenticated(req *http.Request) (*http.Response, error) {
+
+	token, err := c.currentAccessToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.execute(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		err = checkResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	c.tokenMutex.Lock()
+	if !c.canRefresh() {
+		c.tokenMutex.Unlock()
+		return nil, checkResponse(resp)
+	}
+	if c.AccessToken.AccessToken == token {
+		err = c.refreshAccessToken(req.Context())
+	}
+	token = c.AccessToken.AccessToken
+	c.tokenMutex.Unlock()
+
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err = c.execute(retry)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,99 @@
+package cloudfoundry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAccessTokenRefresh(t *testing.T) {
+
+	refreshResponse := `{
+		"access_token": "refreshedtoken",
+		"token_type": "bearer",
+		"refresh_token": "newrefreshtoken",
+		"expires_in": 300
+	}`
+
+	newLoggedInCloudController := func(httpClient *http.Client, expiresAt time.Time) *CloudController {
+		cc := testingCloudController(httpClient, CloudControllerConfig{Username: "cloudmaster", Password: "cloudpass"})
+		cc.TokenURL, _ = url.Parse("http://uaa.mycloudcontroller/oauth/token")
+		cc.AccessToken = &AccessTokenInfo{AccessToken: "oldtoken", RefreshToken: "oldrefreshtoken", ExpiresIn: 300}
+		cc.tokenExpiresAt = expiresAt
+		return cc
+	}
+
+	Convey("Given a logged in CloudController with an expired access token", t, func() {
+
+		Convey("When an app is requested", func() {
+
+			Convey("Then the token is refreshed before the request is sent", func(c C) {
+				refreshCount := 0
+
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == "POST" {
+						refreshCount++
+						b, _ := ioutil.ReadAll(r.Body)
+						c.So(string(b), ShouldEqual, "grant_type=refresh_token&refresh_token=oldrefreshtoken")
+						w.Write([]byte(refreshResponse))
+						return
+					}
+
+					c.So(r.Header.Get("authorization"), ShouldEqual, "Bearer refreshedtoken")
+					w.Write([]byte(`{"guid": "app-guid", "name": "my_app"}`))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc := newLoggedInCloudController(httpClient, time.Now().Add(-time.Minute))
+
+				app, err := cc.GetV3App("app-guid")
+				So(err, ShouldEqual, nil)
+				So(app.Name, ShouldEqual, "my_app")
+				So(refreshCount, ShouldEqual, 1)
+				So(cc.AccessToken.RefreshToken, ShouldEqual, "newrefreshtoken")
+			})
+
+		})
+
+	})
+
+	Convey("Given a logged in CloudController whose token is rejected by the cc API", t, func() {
+
+		Convey("When a resource list is requested", func() {
+
+			Convey("Then the token is refreshed and the request is retried once", func(c C) {
+				requestCount := 0
+
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == "POST" {
+						w.Write([]byte(refreshResponse))
+						return
+					}
+
+					requestCount++
+					if r.Header.Get("authorization") != "Bearer refreshedtoken" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					w.Write([]byte(`{"total_results": 1, "total_pages": 1, "resources": [{"metadata": {"guid": "stack-guid"}, "entity": {"name": "cflinuxfs3"}}]}`))
+				})
+				httpClient, teardown := testingHTTPClient(h)
+				defer teardown()
+
+				cc := newLoggedInCloudController(httpClient, time.Now().Add(time.Hour))
+
+				resources, err := cc.GetResourceList("/v2/stacks")
+				So(err, ShouldEqual, nil)
+				So(len(*resources), ShouldEqual, 1)
+				So(requestCount, ShouldEqual, 2)
+			})
+
+		})
+
+	})
+
+}
@@ -9,21 +9,61 @@ type App struct {
 	UpdatedAt     string           `json:"updated_at"` //"2016-06-08T16:41:26Z",
 	Lifecycle     *LifecycleEntity `json:"lifecycle"`
 	Relationships *Relationships   `json:"relationships"`
+	Metadata      *Metadata        `json:"metadata"`
 	Links         *Links           `json:"links"`
 }
 
+// Lifecycle types of apps and droplets.
+const (
+	LifecycleBuildpack = "buildpack"
+	LifecycleCNB       = "cnb"
+	LifecycleDocker    = "docker"
+)
+
 // LifecycleEntity
 type LifecycleEntity struct {
-	Type string         `json:"type"` //"buildpack"
+	Type string         `json:"type"` //"buildpack", "cnb" or "docker"
 	Data *LifecycleData `json:"data"`
 }
 
-// LifecycleData
+// LifecycleData - The buildpacks and the stack of the buildpack and cnb
+// lifecycles. The data of the docker lifecycle is empty, the image is part
+// of the package and the droplet.
 type LifecycleData struct {
-	Buildpacks []string `json:"buildpacks"` //["java_buildpack"]
+	Buildpacks []string `json:"buildpacks"` //["java_buildpack"] or ["docker://gcr.io/paketo-buildpacks/java"]
 	Stack      string   `json:"stack"`      //cflinuxfs2"
 }
 
+// GetBuildpacks returns the buildpacks of the lifecycle, if any.
+func (l *LifecycleEntity) GetBuildpacks() []string {
+
+	if l == nil || l.Data == nil {
+		return nil
+	}
+
+	return l.Data.Buildpacks
+}
+
+// GetStack returns the stack of the lifecycle or "".
+func (l *LifecycleEntity) GetStack() string {
+
+	if l == nil || l.Data == nil {
+		return ""
+	}
+
+	return l.Data.Stack
+}
+
+// GetType returns the type of the lifecycle, which defaults to buildpack.
+func (l *LifecycleEntity) GetType() string {
+
+	if l == nil || l.Type == "" {
+		return LifecycleBuildpack
+	}
+
+	return l.Type
+}
+
 // Relationships
 type Relationships struct {
 	Space *RelationshipsSpace `json:"space"`
@@ -39,6 +79,16 @@ type SpaceData struct {
 	GUID string `json:"guid"` //"2f35885d-0c9d-4423-83ad-fd05066f8576"
 }
 
+// SpaceGUID returns the guid of the space of the app.
+func (a *App) SpaceGUID() string {
+
+	if a.Relationships == nil || a.Relationships.Space == nil || a.Relationships.Space.Data == nil {
+		return ""
+	}
+
+	return a.Relationships.Space.Data.GUID
+}
+
 // Links
 type Links struct {
 	Self                 *Link     `json:"self"`
@@ -63,8 +113,40 @@ type Link struct {
 	Method string `json:"method"`
 }
 
-// Metadata
+// Metadata - Labels and annotations of a resource.
 type Metadata struct {
-	Labels      string `json:"labels"`
-	Annotations string `json:"annotations"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Label returns the value of the label and whether the label is set.
+func (m *Metadata) Label(key string) (string, bool) {
+
+	if m == nil {
+		return "", false
+	}
+
+	value, ok := m.Labels[key]
+	return value, ok
+}
+
+// Annotation returns the value of the annotation and whether it is set.
+func (m *Metadata) Annotation(key string) (string, bool) {
+
+	if m == nil {
+		return "", false
+	}
+
+	value, ok := m.Annotations[key]
+	return value, ok
+}
+
+// GetLabels returns the labels or nil if the resource has no metadata.
+func (m *Metadata) GetLabels() map[string]string {
+
+	if m == nil {
+		return nil
+	}
+
+	return m.Labels
 }
@@ -0,0 +1,18 @@
+package v3
+
+// Buildpack
+type Buildpack struct {
+	GUID      string           `json:"guid"`
+	Name      string           `json:"name"`      //"ruby_buildpack"
+	State     string           `json:"state"`     //"AWAITING_UPLOAD"
+	Filename  string           `json:"filename"`  //"ruby_buildpack-cflinuxfs3-v1.8.42.zip"
+	Stack     string           `json:"stack"`     //"cflinuxfs3", empty for all stacks
+	Lifecycle string           `json:"lifecycle"` //"buildpack" or "cnb"
+	Position  int              `json:"position"`
+	Enabled   bool             `json:"enabled"`
+	Locked    bool             `json:"locked"`
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+	Metadata  *Metadata        `json:"metadata"`
+	Links     map[string]*Link `json:"links"`
+}
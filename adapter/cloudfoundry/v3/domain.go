@@ -0,0 +1,27 @@
+package v3
+
+// Domain
+type Domain struct {
+	GUID               string               `json:"guid"`
+	Name               string               `json:"name"` //"example.org"
+	Internal           bool                 `json:"internal"`
+	RouterGroup        *RelationshipData    `json:"router_group"`
+	SupportedProtocols []string             `json:"supported_protocols"` //["http"]
+	CreatedAt          string               `json:"created_at"`
+	UpdatedAt          string               `json:"updated_at"`
+	Relationships      *DomainRelationships `json:"relationships"`
+	Metadata           *Metadata            `json:"metadata"`
+	Links              map[string]*Link     `json:"links"`
+}
+
+// DomainRelationships
+type DomainRelationships struct {
+	Organization        *Relationship       `json:"organization"`
+	SharedOrganizations *ToManyRelationship `json:"shared_organizations"`
+}
+
+// Shared reports whether the domain is a shared domain available to all
+// organizations, as opposed to a private domain owned by an organization.
+func (d *Domain) Shared() bool {
+	return d.Relationships == nil || d.Relationships.Organization.GUID() == ""
+}
@@ -0,0 +1,123 @@
+package v3
+
+import (
+	"strings"
+)
+
+// Droplet - The result of staging a package, ready to be run.
+type Droplet struct {
+	GUID              string              `json:"guid"`
+	State             string              `json:"state"` //"STAGED"
+	Error             string              `json:"error"`
+	Lifecycle         *LifecycleEntity    `json:"lifecycle"`
+	Buildpacks        []*DropletBuildpack `json:"buildpacks"`
+	Stack             string              `json:"stack"` //"cflinuxfs3"
+	Image             string              `json:"image"` //only for docker droplets
+	Checksum          *Checksum           `json:"checksum"`
+	ExecutionMetadata string              `json:"execution_metadata"`
+	ProcessTypes      map[string]string   `json:"process_types"`
+	CreatedAt         string              `json:"created_at"`
+	UpdatedAt         string              `json:"updated_at"`
+	Metadata          *Metadata           `json:"metadata"`
+	Links             map[string]*Link    `json:"links"`
+}
+
+// DropletBuildpack - A buildpack used for staging with the detected version.
+type DropletBuildpack struct {
+	Name          string `json:"name"`           //"java_buildpack"
+	DetectOutput  string `json:"detect_output"`  //"java"
+	BuildpackName string `json:"buildpack_name"` //"java"
+	Version       string `json:"version"`        //"4.20"
+}
+
+// Checksum
+type Checksum struct {
+	Type  string `json:"type"` //"sha256"
+	Value string `json:"value"`
+}
+
+// String returns the buildpack with its version, e.g. "java_buildpack 4.20".
+func (b *DropletBuildpack) String() string {
+	return strings.TrimSpace(b.Name + " " + b.Version)
+}
+
+// String returns the checksum prefixed with its type, e.g. "sha256:3a4b...".
+func (c *Checksum) String() string {
+
+	if c == nil || c.Value == "" {
+		return ""
+	}
+
+	return c.Type + ":" + c.Value
+}
+
+// AppGUID returns the guid of the app of the droplet.
+func (d *Droplet) AppGUID() string {
+	return linkGUID(d.Links["app"])
+}
+
+// PackageGUID returns the guid of the package the droplet was staged from.
+func (d *Droplet) PackageGUID() string {
+	return linkGUID(d.Links["package"])
+}
+
+// Package - The source bits or docker image of an app.
+type Package struct {
+	GUID      string           `json:"guid"`
+	Type      string           `json:"type"`  //"bits" or "docker"
+	State     string           `json:"state"` //"READY"
+	Data      *PackageData     `json:"data"`
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+	Metadata  *Metadata        `json:"metadata"`
+	Links     map[string]*Link `json:"links"`
+}
+
+// PackageData
+type PackageData struct {
+	Checksum *Checksum `json:"checksum"` //only for bits
+	Error    string    `json:"error"`
+	Image    string    `json:"image"` //only for docker
+}
+
+// Revision - A snapshot of droplet, environment and processes of an app
+// which can be deployed.
+type Revision struct {
+	GUID          string                 `json:"guid"`
+	Version       int                    `json:"version"`
+	Description   string                 `json:"description"`
+	Deployable    bool                   `json:"deployable"`
+	Droplet       *RelationshipData      `json:"droplet"`
+	CreatedAt     string                 `json:"created_at"`
+	UpdatedAt     string                 `json:"updated_at"`
+	Relationships *RevisionRelationships `json:"relationships"`
+	Metadata      *Metadata              `json:"metadata"`
+	Links         map[string]*Link       `json:"links"`
+}
+
+// RevisionRelationships
+type RevisionRelationships struct {
+	App *Relationship `json:"app"`
+}
+
+// DropletGUID returns the guid of the droplet of the revision.
+func (r *Revision) DropletGUID() string {
+
+	if r.Droplet == nil {
+		return ""
+	}
+
+	return r.Droplet.GUID
+}
+
+// linkGUID returns the last path segment of the link, which is the guid for
+// links to single resources.
+func linkGUID(link *Link) string {
+
+	if link == nil || link.HRef == "" {
+		return ""
+	}
+
+	href := strings.TrimSuffix(link.HRef, "/")
+	return href[strings.LastIndex(href, "/")+1:]
+}
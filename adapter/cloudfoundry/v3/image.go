@@ -0,0 +1,71 @@
+package v3
+
+import (
+	"strings"
+)
+
+// DefaultRegistry is the registry of image references without registry.
+const DefaultRegistry = "docker.io"
+
+// ImageReference - The parts of a docker image reference like
+// "registry.example.org:5000/team/app:1.0" or "busybox@sha256:7cc4...".
+type ImageReference struct {
+	Registry   string //"docker.io"
+	Repository string //"library/busybox"
+	Tag        string //"latest"
+	Digest     string //"sha256:7cc4b5aefd1d0cadf8d97d4350462ba51c694ebca145b08d7d41b41acc8db5aa"
+}
+
+// ParseImageReference splits the image reference into its parts. Missing
+// registries default to docker.io with the library namespace for official
+// images. The tag defaults to latest unless a digest is given.
+func ParseImageReference(reference string) *ImageReference {
+
+	ref := &ImageReference{}
+	rest := strings.TrimPrefix(strings.TrimSpace(reference), "docker://")
+
+	if i := strings.Index(rest, "@"); i >= 0 {
+		ref.Digest = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.Contains(rest[i:], "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	ref.Registry = DefaultRegistry
+	if i := strings.Index(rest, "/"); i >= 0 {
+		first := rest[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Registry = first
+			rest = rest[i+1:]
+		}
+	}
+
+	if ref.Registry == DefaultRegistry && !strings.Contains(rest, "/") {
+		rest = "library/" + rest
+	}
+	ref.Repository = rest
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	return ref
+}
+
+// String returns the fully qualified reference, e.g.
+// "docker.io/library/busybox:latest".
+func (r *ImageReference) String() string {
+
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+
+	return s
+}
@@ -0,0 +1,56 @@
+package v3
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestImageReference(t *testing.T) {
+
+	Convey("Given docker image references in different forms", t, func() {
+
+		Convey("When an official image without registry and tag is parsed", func() {
+
+			ref := ParseImageReference("busybox")
+
+			Convey("Then the registry, the library namespace and the tag are defaulted", func() {
+				So(*ref, ShouldResemble, ImageReference{Registry: "docker.io", Repository: "library/busybox", Tag: "latest"})
+				So(ref.String(), ShouldEqual, "docker.io/library/busybox:latest")
+			})
+
+		})
+
+		Convey("When an image of a private registry with port and tag is parsed", func() {
+
+			ref := ParseImageReference("registry.example.org:5000/team/app:1.0")
+
+			Convey("Then registry, repository and tag are split", func() {
+				So(*ref, ShouldResemble, ImageReference{Registry: "registry.example.org:5000", Repository: "team/app", Tag: "1.0"})
+			})
+
+		})
+
+		Convey("When an image with digest is parsed", func() {
+
+			ref := ParseImageReference("docker://gcr.io/paketo-buildpacks/java@sha256:abc")
+
+			Convey("Then the digest is split and no tag is defaulted", func() {
+				So(*ref, ShouldResemble, ImageReference{Registry: "gcr.io", Repository: "paketo-buildpacks/java", Digest: "sha256:abc"})
+				So(ref.String(), ShouldEqual, "gcr.io/paketo-buildpacks/java@sha256:abc")
+			})
+
+		})
+
+		Convey("When an image of a user on docker hub is parsed", func() {
+
+			ref := ParseImageReference("team/app:2")
+
+			Convey("Then the registry is defaulted without library namespace", func() {
+				So(*ref, ShouldResemble, ImageReference{Registry: "docker.io", Repository: "team/app", Tag: "2"})
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,231 @@
+package v3
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Operators of a LabelRequirement.
+const (
+	LabelExists    = "exists"
+	LabelNotExists = "!exists"
+	LabelEquals    = "="
+	LabelNotEquals = "!="
+	LabelIn        = "in"
+	LabelNotIn     = "notin"
+)
+
+// LabelSelector - A parsed cloud foundry label selector like
+// "team=payments,tier in (backend,api),!deprecated". A resource matches if
+// it fulfills all requirements.
+type LabelSelector []LabelRequirement
+
+// LabelRequirement - A single requirement of a LabelSelector.
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// ParseLabelSelector parses a label selector in cloud foundry syntax:
+//
+//	key             the label is set
+//	!key            the label is not set
+//	key=value       the label has the value (also key==value)
+//	key!=value      the label is not set or has another value
+//	key in (a,b)    the label has one of the values
+//	key notin (a,b) the label is not set or has none of the values
+//
+// Requirements are separated by commas.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+
+	var labelSelector LabelSelector
+
+	for _, part := range splitRequirements(selector) {
+		requirement, err := parseLabelRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		labelSelector = append(labelSelector, *requirement)
+	}
+
+	if len(labelSelector) == 0 {
+		return nil, errors.New("label selector cannot be empty")
+	}
+
+	return labelSelector, nil
+}
+
+// splitRequirements splits the selector at all commas outside parentheses.
+func splitRequirements(selector string) []string {
+
+	var parts []string
+	depth := 0
+	start := 0
+
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, selector[start:])
+
+	result := parts[:0]
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			result = append(result, strings.TrimSpace(part))
+		}
+	}
+
+	return result
+}
+
+func parseLabelRequirement(part string) (*LabelRequirement, error) {
+
+	if strings.HasPrefix(part, "!") && !strings.Contains(part, "=") {
+		return newLabelRequirement(strings.TrimSpace(part[1:]), LabelNotExists, nil, part)
+	}
+
+	if i := strings.Index(part, "!="); i >= 0 {
+		return newLabelRequirement(part[:i], LabelNotEquals, []string{part[i+2:]}, part)
+	}
+
+	if i := strings.Index(part, "=="); i >= 0 {
+		return newLabelRequirement(part[:i], LabelEquals, []string{part[i+2:]}, part)
+	}
+
+	if i := strings.Index(part, "="); i >= 0 {
+		return newLabelRequirement(part[:i], LabelEquals, []string{part[i+1:]}, part)
+	}
+
+	fields := strings.Fields(part)
+	if len(fields) == 1 {
+		return newLabelRequirement(fields[0], LabelExists, nil, part)
+	}
+
+	if len(fields) >= 2 && (fields[1] == LabelIn || fields[1] == LabelNotIn) {
+		set := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part[len(fields[0]):]), fields[1]))
+		if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+			return nil, errors.New("invalid label requirement " + part + ": values must be given in parentheses")
+		}
+
+		values := strings.Split(set[1:len(set)-1], ",")
+
+		return newLabelRequirement(fields[0], fields[1], values, part)
+	}
+
+	return nil, errors.New("invalid label requirement " + part)
+}
+
+func newLabelRequirement(key string, operator string, values []string, part string) (*LabelRequirement, error) {
+
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, " ()!=") {
+		return nil, errors.New("invalid label key in requirement " + part)
+	}
+
+	setOperator := operator == LabelIn || operator == LabelNotIn
+
+	trimmed := values[:0]
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if strings.ContainsAny(value, " ()!=") {
+			return nil, errors.New("invalid label value in requirement " + part)
+		}
+		if setOperator && value == "" {
+			continue
+		}
+		trimmed = append(trimmed, value)
+	}
+	values = trimmed
+
+	if setOperator && len(values) == 0 {
+		return nil, errors.New("invalid label requirement " + part + ": no values given")
+	}
+
+	return &LabelRequirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+// Matches reports whether the labels fulfill all requirements.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Matches reports whether the labels fulfill the requirement.
+func (r *LabelRequirement) Matches(labels map[string]string) bool {
+
+	value, ok := labels[r.Key]
+
+	switch r.Operator {
+	case LabelExists:
+		return ok
+	case LabelNotExists:
+		return !ok
+	case LabelEquals:
+		return ok && value == r.Values[0]
+	case LabelNotEquals:
+		return !ok || value != r.Values[0]
+	case LabelIn:
+		return ok && contains(r.Values, value)
+	case LabelNotIn:
+		return !ok || !contains(r.Values, value)
+	}
+
+	return false
+}
+
+// String returns the selector in cloud foundry syntax as used for the
+// label_selector query parameter.
+func (s LabelSelector) String() string {
+
+	parts := make([]string, 0, len(s))
+	for _, requirement := range s {
+		parts = append(parts, requirement.String())
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// String returns the requirement in cloud foundry syntax.
+func (r *LabelRequirement) String() string {
+
+	switch r.Operator {
+	case LabelExists:
+		return r.Key
+	case LabelNotExists:
+		return "!" + r.Key
+	case LabelEquals, LabelNotEquals:
+		return r.Key + r.Operator + r.Values[0]
+	}
+
+	values := append([]string(nil), r.Values...)
+	sort.Strings(values)
+	return r.Key + " " + r.Operator + " (" + strings.Join(values, ",") + ")"
+}
+
+func contains(values []string, value string) bool {
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
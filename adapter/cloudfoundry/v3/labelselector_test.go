@@ -0,0 +1,90 @@
+package v3
+
+import (
+	"encoding/json"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLabelSelector(t *testing.T) {
+
+	Convey("Given a label selector with all kinds of requirements", t, func() {
+
+		selector := "team=payments, tier in (backend, api),!deprecated,env!=dev,domain,region notin (eu)"
+
+		Convey("When the label selector is parsed", func() {
+
+			labelSelector, err := ParseLabelSelector(selector)
+
+			Convey("Then all requirements are recognized", func() {
+				So(err, ShouldEqual, nil)
+				So(len(labelSelector), ShouldEqual, 6)
+				So(labelSelector[0], ShouldResemble, LabelRequirement{Key: "team", Operator: LabelEquals, Values: []string{"payments"}})
+				So(labelSelector[1], ShouldResemble, LabelRequirement{Key: "tier", Operator: LabelIn, Values: []string{"backend", "api"}})
+				So(labelSelector[2], ShouldResemble, LabelRequirement{Key: "deprecated", Operator: LabelNotExists})
+				So(labelSelector[3], ShouldResemble, LabelRequirement{Key: "env", Operator: LabelNotEquals, Values: []string{"dev"}})
+				So(labelSelector[4], ShouldResemble, LabelRequirement{Key: "domain", Operator: LabelExists})
+				So(labelSelector[5], ShouldResemble, LabelRequirement{Key: "region", Operator: LabelNotIn, Values: []string{"eu"}})
+				So(labelSelector.String(), ShouldEqual, "team=payments,tier in (api,backend),!deprecated,env!=dev,domain,region notin (eu)")
+			})
+
+			Convey("Then matching labels are selected", func() {
+				So(labelSelector.Matches(map[string]string{"team": "payments", "tier": "api", "env": "prod", "domain": "checkout"}), ShouldEqual, true)
+				So(labelSelector.Matches(map[string]string{"team": "payments", "tier": "api", "domain": "checkout", "region": "us"}), ShouldEqual, true)
+			})
+
+			Convey("Then labels violating a requirement are not selected", func() {
+				So(labelSelector.Matches(map[string]string{"team": "payments", "tier": "frontend", "domain": "checkout"}), ShouldEqual, false)
+				So(labelSelector.Matches(map[string]string{"team": "payments", "tier": "api", "domain": "checkout", "deprecated": "true"}), ShouldEqual, false)
+				So(labelSelector.Matches(map[string]string{"team": "payments", "tier": "api", "domain": "checkout", "region": "eu"}), ShouldEqual, false)
+				So(labelSelector.Matches(nil), ShouldEqual, false)
+			})
+
+		})
+
+	})
+
+	Convey("Given an invalid label selector", t, func() {
+
+		Convey("When the label selector is parsed", func() {
+
+			Convey("Then an error message indicates the invalid requirement", func() {
+				_, err := ParseLabelSelector("tier in backend")
+				So(err.Error(), ShouldEqual, "invalid label requirement tier in backend: values must be given in parentheses")
+
+				_, err = ParseLabelSelector("tier in ()")
+				So(err.Error(), ShouldEqual, "invalid label requirement tier in (): no values given")
+
+				_, err = ParseLabelSelector("tier notin ( , )")
+				So(err.Error(), ShouldEqual, "invalid label requirement tier notin ( , ): no values given")
+
+				_, err = ParseLabelSelector(" , ")
+				So(err.Error(), ShouldEqual, "label selector cannot be empty")
+			})
+
+		})
+
+	})
+
+	Convey("Given an app with labels and annotations", t, func() {
+
+		Convey("When the app is decoded", func() {
+
+			Convey("Then labels and annotations are available as maps", func() {
+				var app App
+				err := json.Unmarshal([]byte(`{"guid": "a1", "metadata": {"labels": {"team": "payments"}, "annotations": {"contact": "payments@example.org"}}}`), &app)
+				So(err, ShouldEqual, nil)
+
+				team, ok := app.Metadata.Label("team")
+				So(ok, ShouldEqual, true)
+				So(team, ShouldEqual, "payments")
+
+				contact, _ := app.Metadata.Annotation("contact")
+				So(contact, ShouldEqual, "payments@example.org")
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,59 @@
+package v3
+
+// AppLineage - The builds of an app: its packages, the droplets staged from
+// them and the deployed revisions running the droplets.
+type AppLineage struct {
+	CurrentDroplet *Droplet
+	// Droplets are all droplets of the app, newest first.
+	Droplets []*Droplet
+	// Packages are all packages of the app, newest first.
+	Packages []*Package
+	// DeployedRevisions are sorted by version.
+	DeployedRevisions []*Revision
+}
+
+// Droplet returns the droplet with the guid or nil.
+func (l *AppLineage) Droplet(guid string) *Droplet {
+
+	if l.CurrentDroplet != nil && l.CurrentDroplet.GUID == guid {
+		return l.CurrentDroplet
+	}
+
+	for _, d := range l.Droplets {
+		if d.GUID == guid {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// Package returns the package with the guid or nil.
+func (l *AppLineage) Package(guid string) *Package {
+
+	for _, p := range l.Packages {
+		if p.GUID == guid {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// NewerDroplets returns the staged droplets which were created after the
+// current droplet and are not running yet.
+func (l *AppLineage) NewerDroplets() []*Droplet {
+
+	var result []*Droplet
+	if l.CurrentDroplet == nil {
+		return result
+	}
+
+	for _, d := range l.Droplets {
+		if d.GUID != l.CurrentDroplet.GUID && d.State == "STAGED" && d.CreatedAt > l.CurrentDroplet.CreatedAt {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,347 @@
+package v3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Getter sends a GET request for the given href to the cc API and decodes the
+// JSON response into v. The href is either a path relative to the API URL or
+// an absolute URL as found in pagination links.
+type Getter interface {
+	GetJSON(ctx context.Context, href string, v interface{}) error
+}
+
+// MaxPerPage is the largest page size accepted by the v3 API.
+const MaxPerPage = 5000
+
+// Pagination
+type Pagination struct {
+	TotalResults int   `json:"total_results"`
+	TotalPages   int   `json:"total_pages"`
+	First        *Link `json:"first"`
+	Last         *Link `json:"last"`
+	Next         *Link `json:"next"`
+	Previous     *Link `json:"previous"`
+}
+
+// ListResponse - A single page of a v3 list.
+type ListResponse struct {
+	Pagination Pagination        `json:"pagination"`
+	Resources  []json.RawMessage `json:"resources"`
+	Included   Included          `json:"included"`
+}
+
+// Included - Side-loaded resources requested with include=, keyed by the
+// resource type (e.g. "spaces", "organizations").
+type Included map[string][]json.RawMessage
+
+// ListQuery - Query parameters of v3 list requests.
+type ListQuery struct {
+	PerPage int
+	OrderBy string
+	// Filters maps filter names like names, guids, space_guids or
+	// organization_guids to the accepted values.
+	Filters map[string][]string
+	Include []string
+	// LabelSelector selects resources by their labels, see
+	// ParseLabelSelector for the syntax.
+	LabelSelector string
+}
+
+// Filter adds values to the named filter and returns the query.
+func (q *ListQuery) Filter(name string, values ...string) *ListQuery {
+
+	if q.Filters == nil {
+		q.Filters = make(map[string][]string)
+	}
+	q.Filters[name] = append(q.Filters[name], values...)
+
+	return q
+}
+
+// Values returns the query as url values.
+func (q *ListQuery) Values() url.Values {
+
+	values := url.Values{}
+	if q == nil {
+		return values
+	}
+
+	if q.PerPage > 0 {
+		values.Set("per_page", strconv.Itoa(q.PerPage))
+	}
+
+	if q.OrderBy != "" {
+		values.Set("order_by", q.OrderBy)
+	}
+
+	for name, filter := range q.Filters {
+		if len(filter) > 0 {
+			values.Set(name, strings.Join(filter, ","))
+		}
+	}
+
+	if len(q.Include) > 0 {
+		values.Set("include", strings.Join(q.Include, ","))
+	}
+
+	if q.LabelSelector != "" {
+		values.Set("label_selector", q.LabelSelector)
+	}
+
+	return values
+}
+
+// ListIterator walks over all resources of a v3 list. Further pages are
+// requested by following pagination.next.href.
+//
+//	it := v3.NewListIterator(getter, "/v3/apps", query)
+//	for it.Next(ctx) {
+//		var app v3.App
+//		err := it.Decode(&app)
+//		...
+//	}
+//	if it.Err() != nil {
+//		...
+//	}
+type ListIterator struct {
+	getter   Getter
+	next     string
+	page     *ListResponse
+	index    int
+	included Included
+	err      error
+}
+
+// NewListIterator returns a ListIterator for the list at the given path.
+func NewListIterator(getter Getter, path string, query *ListQuery) *ListIterator {
+	return &ListIterator{getter: getter, next: pageHref(path, query, 0), index: -1, included: make(Included)}
+}
+
+// pageHref returns the href of the page of the list at the given path. Page
+// zero means the first page without page parameter.
+func pageHref(path string, query *ListQuery, page int) string {
+
+	values := query.Values()
+	if page > 0 {
+		values.Set("page", strconv.Itoa(page))
+	}
+
+	if len(values) == 0 {
+		return path
+	}
+
+	return path + "?" + values.Encode()
+}
+
+// Next advances to the next resource. It returns false when all resources
+// have been visited or an error occurred.
+func (it *ListIterator) Next(ctx context.Context) bool {
+
+	if it.err != nil {
+		return false
+	}
+
+	for it.page == nil || it.index+1 >= len(it.page.Resources) {
+		if it.next == "" {
+			return false
+		}
+
+		err := ctx.Err()
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		var page ListResponse
+		err = it.getter.GetJSON(ctx, it.next, &page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = &page
+		it.index = -1
+		it.next = ""
+		if page.Pagination.Next != nil {
+			it.next = page.Pagination.Next.HRef
+		}
+
+		for resourceType, resources := range page.Included {
+			it.included[resourceType] = append(it.included[resourceType], resources...)
+		}
+	}
+
+	it.index++
+	return true
+}
+
+// Resource returns the raw JSON of the current resource.
+func (it *ListIterator) Resource() json.RawMessage {
+
+	if it.page == nil || it.index < 0 {
+		return nil
+	}
+
+	return it.page.Resources[it.index]
+}
+
+// Decode decodes the current resource into v.
+func (it *ListIterator) Decode(v interface{}) error {
+
+	resource := it.Resource()
+	if resource == nil {
+		return errors.New("no current resource, call Next first")
+	}
+
+	return json.Unmarshal(resource, v)
+}
+
+// Pagination returns the pagination details of the current page.
+func (it *ListIterator) Pagination() *Pagination {
+
+	if it.page == nil {
+		return nil
+	}
+
+	return &it.page.Pagination
+}
+
+// Included returns the side-loaded resources of all pages visited so far.
+func (it *ListIterator) Included() Included {
+	return it.included
+}
+
+// Err returns the error which stopped the iteration.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+// List calls fn with every resource of the list at the given path and
+// returns the side-loaded resources of all pages.
+func List(ctx context.Context, getter Getter, path string, query *ListQuery, fn func(resource json.RawMessage) error) (Included, error) {
+
+	it := NewListIterator(getter, path, query)
+	for it.Next(ctx) {
+		err := fn(it.Resource())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+
+	return it.Included(), nil
+}
+
+// ListConcurrently is like List but fetches the pages after the first one
+// concurrently with up to workers requests at a time. The number of pages is
+// taken from pagination.total_pages of the first page, without it further
+// pages are requested one by one via pagination.next.href. fn is called in
+// the order of the pages and never concurrently. Fetching stops as soon as
+// the context is cancelled or a page fails.
+func ListConcurrently(ctx context.Context, getter Getter, path string, query *ListQuery, workers int, fn func(resource json.RawMessage) error) (Included, error) {
+
+	var first ListResponse
+	err := getter.GetJSON(ctx, pageHref(path, query, 0), &first)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := first.Pagination.TotalPages
+	if totalPages <= 1 {
+		it := &ListIterator{getter: getter, page: &first, index: -1, included: make(Included)}
+		if first.Pagination.Next != nil {
+			it.next = first.Pagination.Next.HRef
+		}
+		for resourceType, resources := range first.Included {
+			it.included[resourceType] = append(it.included[resourceType], resources...)
+		}
+
+		for it.Next(ctx) {
+			err := fn(it.Resource())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if it.Err() != nil {
+			return nil, it.Err()
+		}
+
+		return it.Included(), nil
+	}
+
+	pages := make([]*ListResponse, totalPages)
+	pages[0] = &first
+
+	pageCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var pageErr error
+	pageNumbers := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < totalPages-1; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pageNumbers {
+				var response ListResponse
+				err := getter.GetJSON(pageCtx, pageHref(path, query, page), &response)
+
+				mutex.Lock()
+				if err != nil && pageErr == nil {
+					pageErr = err
+					cancel()
+				}
+				if err == nil {
+					pages[page-1] = &response
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for page := 2; page <= totalPages && pageCtx.Err() == nil; page++ {
+		select {
+		case pageNumbers <- page:
+		case <-pageCtx.Done():
+		}
+	}
+	close(pageNumbers)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if pageErr != nil {
+		return nil, pageErr
+	}
+
+	included := make(Included)
+	for _, page := range pages {
+		for _, resource := range page.Resources {
+			err := fn(resource)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for resourceType, resources := range page.Included {
+			included[resourceType] = append(included[resourceType], resources...)
+		}
+	}
+
+	return included, nil
+}
@@ -0,0 +1,165 @@
+package v3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+)
+
+// testingGetter serves the pages of a v3 list from memory.
+type testingGetter struct {
+	mutex     sync.Mutex
+	pages     map[string]string
+	requested []string
+}
+
+func (g *testingGetter) GetJSON(ctx context.Context, href string, v interface{}) error {
+	g.mutex.Lock()
+	g.requested = append(g.requested, href)
+	g.mutex.Unlock()
+
+	page, ok := g.pages[href]
+	if !ok {
+		return errors.New("unexpected request " + href)
+	}
+
+	return json.Unmarshal([]byte(page), v)
+}
+
+func TestListIterator(t *testing.T) {
+
+	getter := &testingGetter{pages: map[string]string{
+		"/v3/apps?include=space&order_by=name&per_page=2&space_guids=s1%2Cs2": `{
+			"pagination": {"total_results": 3, "total_pages": 2, "next": {"href": "https://api.example.org/v3/apps?page=2&per_page=2"}},
+			"resources": [{"guid": "a1", "name": "app1"}, {"guid": "a2", "name": "app2"}],
+			"included": {"spaces": [{"guid": "s1", "name": "space1"}]}
+		}`,
+		"https://api.example.org/v3/apps?page=2&per_page=2": `{
+			"pagination": {"total_results": 3, "total_pages": 2, "next": null},
+			"resources": [{"guid": "a3", "name": "app3"}],
+			"included": {"spaces": [{"guid": "s2", "name": "space2"}]}
+		}`,
+	}}
+
+	query := &ListQuery{PerPage: 2, OrderBy: "name", Include: []string{"space"}}
+	query.Filter("space_guids", "s1", "s2")
+
+	Convey("Given a v3 list with two pages", t, func() {
+
+		getter.requested = nil
+
+		Convey("When the list is iterated", func() {
+
+			Convey("Then all resources are visited by following pagination.next.href", func() {
+				it := NewListIterator(getter, "/v3/apps", query)
+
+				var names []string
+				for it.Next(context.Background()) {
+					var app App
+					So(it.Decode(&app), ShouldEqual, nil)
+					names = append(names, app.Name)
+				}
+
+				So(it.Err(), ShouldEqual, nil)
+				So(names, ShouldResemble, []string{"app1", "app2", "app3"})
+				So(len(getter.requested), ShouldEqual, 2)
+				So(len(it.Included()["spaces"]), ShouldEqual, 2)
+				So(it.Pagination().TotalResults, ShouldEqual, 3)
+			})
+
+		})
+
+		Convey("When the callback fails", func() {
+
+			Convey("Then listing stops with the error of the callback", func() {
+				count := 0
+				_, err := List(context.Background(), getter, "/v3/apps", query, func(resource json.RawMessage) error {
+					count++
+					return fmt.Errorf("stop at %d", count)
+				})
+
+				So(err.Error(), ShouldEqual, "stop at 1")
+				So(len(getter.requested), ShouldEqual, 1)
+			})
+
+		})
+
+	})
+
+	Convey("Given a v3 list with three pages reporting the total number of pages", t, func() {
+
+		pagedGetter := &testingGetter{pages: map[string]string{
+			"/v3/apps?per_page=1": `{
+				"pagination": {"total_results": 3, "total_pages": 3, "next": {"href": "https://api.example.org/v3/apps?page=2&per_page=1"}},
+				"resources": [{"guid": "a1", "name": "app1"}]
+			}`,
+			"/v3/apps?page=2&per_page=1": `{
+				"pagination": {"total_results": 3, "total_pages": 3},
+				"resources": [{"guid": "a2", "name": "app2"}],
+				"included": {"spaces": [{"guid": "s1", "name": "space1"}]}
+			}`,
+			"/v3/apps?page=3&per_page=1": `{
+				"pagination": {"total_results": 3, "total_pages": 3},
+				"resources": [{"guid": "a3", "name": "app3"}]
+			}`,
+		}}
+
+		Convey("When the list is fetched concurrently", func() {
+
+			var names []string
+			included, err := ListConcurrently(context.Background(), pagedGetter, "/v3/apps", &ListQuery{PerPage: 1}, 2, func(resource json.RawMessage) error {
+				var app App
+				err := json.Unmarshal(resource, &app)
+				names = append(names, app.Name)
+				return err
+			})
+
+			Convey("Then all pages are requested by page number and visited in order", func() {
+				So(err, ShouldEqual, nil)
+				So(names, ShouldResemble, []string{"app1", "app2", "app3"})
+				So(len(pagedGetter.requested), ShouldEqual, 3)
+				So(len(included["spaces"]), ShouldEqual, 1)
+			})
+
+		})
+
+	})
+
+	Convey("Given a v3 list without the total number of pages", t, func() {
+
+		linkedGetter := &testingGetter{pages: map[string]string{
+			"/v3/apps": `{
+				"pagination": {"total_results": 3, "next": {"href": "https://api.example.org/v3/apps?page=2"}},
+				"resources": [{"guid": "a1", "name": "app1"}, {"guid": "a2", "name": "app2"}]
+			}`,
+			"https://api.example.org/v3/apps?page=2": `{
+				"pagination": {"total_results": 3},
+				"resources": [{"guid": "a3", "name": "app3"}]
+			}`,
+		}}
+
+		Convey("When the list is fetched concurrently", func() {
+
+			var names []string
+			_, err := ListConcurrently(context.Background(), linkedGetter, "/v3/apps", nil, 2, func(resource json.RawMessage) error {
+				var app App
+				err := json.Unmarshal(resource, &app)
+				names = append(names, app.Name)
+				return err
+			})
+
+			Convey("Then the pages are requested via pagination.next.href", func() {
+				So(err, ShouldEqual, nil)
+				So(names, ShouldResemble, []string{"app1", "app2", "app3"})
+				So(linkedGetter.requested, ShouldResemble, []string{"/v3/apps", "https://api.example.org/v3/apps?page=2"})
+			})
+
+		})
+
+	})
+
+}
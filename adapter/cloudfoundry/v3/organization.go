@@ -0,0 +1,28 @@
+package v3
+
+// Organization
+type Organization struct {
+	GUID          string                     `json:"guid"`
+	Name          string                     `json:"name"`
+	Suspended     bool                       `json:"suspended"`
+	CreatedAt     string                     `json:"created_at"`
+	UpdatedAt     string                     `json:"updated_at"`
+	Relationships *OrganizationRelationships `json:"relationships"`
+	Metadata      *Metadata                  `json:"metadata"`
+	Links         map[string]*Link           `json:"links"`
+}
+
+// OrganizationRelationships
+type OrganizationRelationships struct {
+	Quota *Relationship `json:"quota"`
+}
+
+// QuotaGUID returns the guid of the organization quota.
+func (o *Organization) QuotaGUID() string {
+
+	if o.Relationships == nil {
+		return ""
+	}
+
+	return o.Relationships.Quota.GUID()
+}
@@ -0,0 +1,121 @@
+package v3
+
+import (
+	"strconv"
+	"time"
+)
+
+// Process - A process type of an app like web or worker.
+type Process struct {
+	GUID          string                `json:"guid"`
+	Type          string                `json:"type"`    //"web"
+	Command       string                `json:"command"` //"rackup"
+	Instances     int                   `json:"instances"`
+	MemoryInMB    int                   `json:"memory_in_mb"`
+	DiskInMB      int                   `json:"disk_in_mb"`
+	HealthCheck   *HealthCheck          `json:"health_check"`
+	CreatedAt     string                `json:"created_at"`
+	UpdatedAt     string                `json:"updated_at"`
+	Relationships *ProcessRelationships `json:"relationships"`
+	Metadata      *Metadata             `json:"metadata"`
+	Links         map[string]*Link      `json:"links"`
+}
+
+// ProcessRelationships
+type ProcessRelationships struct {
+	App      *Relationship `json:"app"`
+	Revision *Relationship `json:"revision"`
+}
+
+// HealthCheck
+type HealthCheck struct {
+	Type string           `json:"type"` //"port", "process" or "http"
+	Data *HealthCheckData `json:"data"`
+}
+
+// HealthCheckData
+type HealthCheckData struct {
+	Timeout           *int   `json:"timeout"`
+	InvocationTimeout *int   `json:"invocation_timeout"`
+	Endpoint          string `json:"endpoint"` //"/health", only for http
+}
+
+// AppGUID returns the guid of the app of the process.
+func (p *Process) AppGUID() string {
+
+	if p.Relationships == nil {
+		return ""
+	}
+
+	return p.Relationships.App.GUID()
+}
+
+// String returns the health check type with the endpoint of http health
+// checks, e.g. "http /health".
+func (h *HealthCheck) String() string {
+
+	if h == nil {
+		return ""
+	}
+
+	if h.Data != nil && h.Data.Endpoint != "" {
+		return h.Type + " " + h.Data.Endpoint
+	}
+
+	return h.Type
+}
+
+// ProcessStats - The response of /v3/processes/:guid/stats.
+type ProcessStats struct {
+	Resources []*ProcessInstanceStats `json:"resources"`
+}
+
+// ProcessInstanceStats - The runtime state of an instance of a process.
+type ProcessInstanceStats struct {
+	Type      string        `json:"type"`
+	Index     int           `json:"index"`
+	State     string        `json:"state"` //"RUNNING", "CRASHED", "STARTING" or "DOWN"
+	Host      string        `json:"host"`
+	Uptime    int64         `json:"uptime"`     //seconds
+	MemQuota  int64         `json:"mem_quota"`  //bytes
+	DiskQuota int64         `json:"disk_quota"` //bytes
+	Usage     *ProcessUsage `json:"usage"`
+}
+
+// ProcessUsage
+type ProcessUsage struct {
+	Time string  `json:"time"`
+	CPU  float64 `json:"cpu"`  //0.0 to 1.0 per core
+	Mem  int64   `json:"mem"`  //bytes
+	Disk int64   `json:"disk"` //bytes
+}
+
+// Running reports whether the instance is running.
+func (s *ProcessInstanceStats) Running() bool {
+	return s.State == "RUNNING"
+}
+
+// UptimeString returns the uptime as duration, e.g. "26h3m0s".
+func (s *ProcessInstanceStats) UptimeString() string {
+	return (time.Duration(s.Uptime) * time.Second).String()
+}
+
+// CPUString returns the cpu usage in percent, e.g. "12.5%".
+func (s *ProcessInstanceStats) CPUString() string {
+
+	if s.Usage == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(s.Usage.CPU*100, 'f', 1, 64) + "%"
+}
+
+// MemoryString returns memory usage and quota in MB, e.g. "300 MB / 1024 MB".
+func (s *ProcessInstanceStats) MemoryString() string {
+
+	if s.Usage == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(s.Usage.Mem/(1024*1024), 10) + " MB / " + strconv.FormatInt(s.MemQuota/(1024*1024), 10) + " MB"
+}
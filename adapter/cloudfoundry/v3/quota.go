@@ -0,0 +1,67 @@
+package v3
+
+// OrganizationQuota
+type OrganizationQuota struct {
+	GUID          string                          `json:"guid"`
+	Name          string                          `json:"name"`
+	CreatedAt     string                          `json:"created_at"`
+	UpdatedAt     string                          `json:"updated_at"`
+	Apps          *QuotaApps                      `json:"apps"`
+	Services      *QuotaServices                  `json:"services"`
+	Routes        *QuotaRoutes                    `json:"routes"`
+	Domains       *QuotaDomains                   `json:"domains"`
+	Relationships *OrganizationQuotaRelationships `json:"relationships"`
+	Links         map[string]*Link                `json:"links"`
+}
+
+// OrganizationQuotaRelationships
+type OrganizationQuotaRelationships struct {
+	Organizations *ToManyRelationship `json:"organizations"`
+}
+
+// SpaceQuota
+type SpaceQuota struct {
+	GUID          string                   `json:"guid"`
+	Name          string                   `json:"name"`
+	CreatedAt     string                   `json:"created_at"`
+	UpdatedAt     string                   `json:"updated_at"`
+	Apps          *QuotaApps               `json:"apps"`
+	Services      *QuotaServices           `json:"services"`
+	Routes        *QuotaRoutes             `json:"routes"`
+	Relationships *SpaceQuotaRelationships `json:"relationships"`
+	Links         map[string]*Link         `json:"links"`
+}
+
+// SpaceQuotaRelationships
+type SpaceQuotaRelationships struct {
+	Organization *Relationship       `json:"organization"`
+	Spaces       *ToManyRelationship `json:"spaces"`
+}
+
+// QuotaApps - App limits of a quota. A nil limit means unlimited.
+type QuotaApps struct {
+	TotalMemoryInMB      *int `json:"total_memory_in_mb"`
+	PerProcessMemoryInMB *int `json:"per_process_memory_in_mb"`
+	LogRateLimitInBPS    *int `json:"log_rate_limit_in_bytes_per_second"`
+	TotalInstances       *int `json:"total_instances"`
+	PerAppTasks          *int `json:"per_app_tasks"`
+}
+
+// QuotaServices - Service limits of a quota. A nil limit means unlimited.
+type QuotaServices struct {
+	PaidServicesAllowed   bool `json:"paid_services_allowed"`
+	TotalServiceInstances *int `json:"total_service_instances"`
+	TotalServiceKeys      *int `json:"total_service_keys"`
+}
+
+// QuotaRoutes - Route limits of a quota. A nil limit means unlimited.
+type QuotaRoutes struct {
+	TotalRoutes        *int `json:"total_routes"`
+	TotalReservedPorts *int `json:"total_reserved_ports"`
+}
+
+// QuotaDomains - Domain limits of an organization quota. A nil limit means
+// unlimited.
+type QuotaDomains struct {
+	TotalDomains *int `json:"total_domains"`
+}
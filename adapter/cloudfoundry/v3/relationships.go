@@ -0,0 +1,41 @@
+package v3
+
+// Relationship - A to-one relationship to another resource.
+type Relationship struct {
+	Data *RelationshipData `json:"data"`
+}
+
+// ToManyRelationship - A to-many relationship to other resources.
+type ToManyRelationship struct {
+	Data []RelationshipData `json:"data"`
+}
+
+// RelationshipData
+type RelationshipData struct {
+	GUID string `json:"guid"`
+}
+
+// GUID returns the guid of the related resource or "" if there is none.
+func (r *Relationship) GUID() string {
+
+	if r == nil || r.Data == nil {
+		return ""
+	}
+
+	return r.Data.GUID
+}
+
+// GUIDs returns the guids of all related resources.
+func (r *ToManyRelationship) GUIDs() []string {
+
+	if r == nil {
+		return nil
+	}
+
+	guids := make([]string, 0, len(r.Data))
+	for _, d := range r.Data {
+		guids = append(guids, d.GUID)
+	}
+
+	return guids
+}
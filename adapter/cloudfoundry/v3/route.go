@@ -0,0 +1,109 @@
+package v3
+
+import (
+	"strconv"
+)
+
+// Route
+type Route struct {
+	GUID          string              `json:"guid"`
+	Protocol      string              `json:"protocol"` //"http" or "tcp"
+	Host          string              `json:"host"`     //"my-app"
+	Path          string              `json:"path"`     //"/api"
+	Port          *int                `json:"port"`     //only for tcp routes
+	URL           string              `json:"url"`      //"my-app.example.org/api"
+	CreatedAt     string              `json:"created_at"`
+	UpdatedAt     string              `json:"updated_at"`
+	Destinations  []*RouteDestination `json:"destinations"`
+	Relationships *RouteRelationships `json:"relationships"`
+	Metadata      *Metadata           `json:"metadata"`
+	Links         map[string]*Link    `json:"links"`
+}
+
+// RouteRelationships
+type RouteRelationships struct {
+	Space  *Relationship `json:"space"`
+	Domain *Relationship `json:"domain"`
+}
+
+// RouteDestination - A process of an app receiving the traffic of a route.
+type RouteDestination struct {
+	GUID     string               `json:"guid"`
+	App      *RouteDestinationApp `json:"app"`
+	Weight   *int                 `json:"weight"`
+	Port     int                  `json:"port"`     //8080
+	Protocol string               `json:"protocol"` //"http1", "http2" or "tcp"
+}
+
+// RouteDestinationApp
+type RouteDestinationApp struct {
+	GUID    string                      `json:"guid"`
+	Process *RouteDestinationAppProcess `json:"process"`
+}
+
+// RouteDestinationAppProcess
+type RouteDestinationAppProcess struct {
+	Type string `json:"type"` //"web"
+}
+
+// RouteDestinations - The response of /v3/routes/:guid/destinations.
+type RouteDestinations struct {
+	Destinations []*RouteDestination `json:"destinations"`
+	Links        map[string]*Link    `json:"links"`
+}
+
+// DomainGUID returns the guid of the domain of the route.
+func (r *Route) DomainGUID() string {
+
+	if r.Relationships == nil {
+		return ""
+	}
+
+	return r.Relationships.Domain.GUID()
+}
+
+// SpaceGUID returns the guid of the space of the route.
+func (r *Route) SpaceGUID() string {
+
+	if r.Relationships == nil {
+		return ""
+	}
+
+	return r.Relationships.Space.GUID()
+}
+
+// DestinationsOf returns the destinations of the route pointing to the app.
+func (r *Route) DestinationsOf(appGUID string) []*RouteDestination {
+
+	var destinations []*RouteDestination
+	for _, d := range r.Destinations {
+		if d.App != nil && d.App.GUID == appGUID {
+			destinations = append(destinations, d)
+		}
+	}
+
+	return destinations
+}
+
+// ProcessType returns the process type receiving the traffic, "web" if none
+// is given.
+func (d *RouteDestination) ProcessType() string {
+
+	if d.App == nil || d.App.Process == nil || d.App.Process.Type == "" {
+		return "web"
+	}
+
+	return d.App.Process.Type
+}
+
+// Description returns port, protocol and process type of the destination,
+// e.g. "8080 http1 (web)".
+func (d *RouteDestination) Description() string {
+
+	description := strconv.Itoa(d.Port)
+	if d.Protocol != "" {
+		description += " " + d.Protocol
+	}
+
+	return description + " (" + d.ProcessType() + ")"
+}
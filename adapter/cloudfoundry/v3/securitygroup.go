@@ -0,0 +1,147 @@
+package v3
+
+import (
+	"strings"
+)
+
+// Lifecycles to which a security group applies.
+const (
+	SecurityGroupRunning = "running"
+	SecurityGroupStaging = "staging"
+)
+
+// SecurityGroup - An application security group controlling the egress
+// traffic of the apps in the spaces it is bound to.
+type SecurityGroup struct {
+	GUID            string                      `json:"guid"`
+	Name            string                      `json:"name"` //"public_networks"
+	GloballyEnabled *SecurityGroupGlobal        `json:"globally_enabled"`
+	Rules           []*SecurityGroupRule        `json:"rules"`
+	CreatedAt       string                      `json:"created_at"`
+	UpdatedAt       string                      `json:"updated_at"`
+	Relationships   *SecurityGroupRelationships `json:"relationships"`
+	Links           map[string]*Link            `json:"links"`
+}
+
+// SecurityGroupGlobal
+type SecurityGroupGlobal struct {
+	Running bool `json:"running"`
+	Staging bool `json:"staging"`
+}
+
+// SecurityGroupRelationships
+type SecurityGroupRelationships struct {
+	RunningSpaces *ToManyRelationship `json:"running_spaces"`
+	StagingSpaces *ToManyRelationship `json:"staging_spaces"`
+}
+
+// SecurityGroupRule
+type SecurityGroupRule struct {
+	Protocol    string `json:"protocol"`    //"tcp", "udp", "icmp" or "all"
+	Destination string `json:"destination"` //"10.10.10.0/24" or "10.10.10.0-10.10.11.255"
+	Ports       string `json:"ports"`       //"443,80,8080" or "8080-8090"
+	Type        *int   `json:"type"`
+	Code        *int   `json:"code"`
+	Description string `json:"description"`
+	Log         bool   `json:"log"`
+}
+
+// Global reports whether the group applies to all spaces in the lifecycle.
+func (g *SecurityGroup) Global(lifecycle string) bool {
+
+	if g.GloballyEnabled == nil {
+		return false
+	}
+
+	switch lifecycle {
+	case SecurityGroupRunning:
+		return g.GloballyEnabled.Running
+	case SecurityGroupStaging:
+		return g.GloballyEnabled.Staging
+	}
+
+	return false
+}
+
+// AppliesTo reports whether the group applies to the apps of the space in
+// the lifecycle, either as global default or bound to the space.
+func (g *SecurityGroup) AppliesTo(spaceGUID string, lifecycle string) bool {
+
+	if g.Global(lifecycle) {
+		return true
+	}
+
+	if g.Relationships == nil {
+		return false
+	}
+
+	spaces := g.Relationships.RunningSpaces
+	if lifecycle == SecurityGroupStaging {
+		spaces = g.Relationships.StagingSpaces
+	}
+
+	for _, guid := range spaces.GUIDs() {
+		if guid == spaceGUID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Broad reports whether any rule of the group is overly broad.
+func (g *SecurityGroup) Broad() bool {
+
+	for _, r := range g.Rules {
+		if r.Broad() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllDestinations reports whether the rule allows every IPv4 or IPv6
+// address, e.g. 0.0.0.0/0.
+func (r *SecurityGroupRule) AllDestinations() bool {
+
+	for _, destination := range strings.Split(r.Destination, ",") {
+		switch strings.TrimSpace(destination) {
+		case "0.0.0.0/0", "0.0.0.0-255.255.255.255", "::/0", "*":
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllPorts reports whether the rule allows every port of the destination.
+// ICMP rules have no ports.
+func (r *SecurityGroupRule) AllPorts() bool {
+
+	switch r.Protocol {
+	case "all":
+		return true
+	case "icmp":
+		return false
+	}
+
+	ports := strings.TrimSpace(r.Ports)
+	return ports == "" || ports == "1-65535" || ports == "0-65535"
+}
+
+// Broad reports whether the rule allows all destinations on all ports.
+func (r *SecurityGroupRule) Broad() bool {
+	return r.AllDestinations() && r.AllPorts()
+}
+
+// String returns the rule in a short form, e.g. "tcp 10.0.0.0/8:443".
+func (r *SecurityGroupRule) String() string {
+
+	s := r.Protocol + " " + r.Destination
+	if r.Ports != "" {
+		s += ":" + r.Ports
+	}
+
+	return s
+}
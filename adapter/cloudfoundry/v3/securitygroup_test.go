@@ -0,0 +1,58 @@
+package v3
+
+import (
+	"encoding/json"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSecurityGroup(t *testing.T) {
+
+	Convey("Given a security group bound to a space and one enabled globally", t, func() {
+
+		var bound, global SecurityGroup
+		json.Unmarshal([]byte(`{"guid": "bound-guid", "name": "backend",
+			"globally_enabled": {"running": false, "staging": false},
+			"rules": [{"protocol": "tcp", "destination": "10.0.0.0/8", "ports": "443"}],
+			"relationships": {"running_spaces": {"data": [{"guid": "space-guid"}]}, "staging_spaces": {"data": []}}}`), &bound)
+		json.Unmarshal([]byte(`{"guid": "global-guid", "name": "public_networks",
+			"globally_enabled": {"running": true, "staging": true},
+			"rules": [{"protocol": "icmp", "destination": "0.0.0.0/0", "type": -1, "code": -1},
+				{"protocol": "all", "destination": "0.0.0.0-9.255.255.255,0.0.0.0/0"}]}`), &global)
+
+		Convey("When the spaces of the groups are checked", func() {
+
+			Convey("Then bound groups apply to their spaces in the bound lifecycle only", func() {
+				So(bound.AppliesTo("space-guid", SecurityGroupRunning), ShouldEqual, true)
+				So(bound.AppliesTo("space-guid", SecurityGroupStaging), ShouldEqual, false)
+				So(bound.AppliesTo("other-space-guid", SecurityGroupRunning), ShouldEqual, false)
+			})
+
+			Convey("Then global groups apply to all spaces", func() {
+				So(global.AppliesTo("other-space-guid", SecurityGroupRunning), ShouldEqual, true)
+				So(global.AppliesTo("other-space-guid", SecurityGroupStaging), ShouldEqual, true)
+			})
+
+		})
+
+		Convey("When the rules of the groups are checked", func() {
+
+			Convey("Then rules to all destinations on all ports are overly broad", func() {
+				So(bound.Broad(), ShouldEqual, false)
+				So(global.Broad(), ShouldEqual, true)
+				So(global.Rules[0].Broad(), ShouldEqual, false)
+				So(global.Rules[1].Broad(), ShouldEqual, true)
+				So((&SecurityGroupRule{Protocol: "tcp", Destination: "0.0.0.0/0", Ports: "1-65535"}).Broad(), ShouldEqual, true)
+				So((&SecurityGroupRule{Protocol: "tcp", Destination: "0.0.0.0/0", Ports: "443"}).Broad(), ShouldEqual, false)
+			})
+
+			Convey("Then rules are described in a short form", func() {
+				So(bound.Rules[0].String(), ShouldEqual, "tcp 10.0.0.0/8:443")
+				So(global.Rules[1].String(), ShouldEqual, "all 0.0.0.0-9.255.255.255,0.0.0.0/0")
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,167 @@
+package v3
+
+// The service models deliberately leave out credentials, syslog drain and
+// route service urls, so they can never end up in a diagram.
+
+// ServiceCredentialBinding - A binding of a service instance to an app
+// (type "app") or a service key (type "key").
+type ServiceCredentialBinding struct {
+	GUID          string                                 `json:"guid"`
+	Type          string                                 `json:"type"` //"app" or "key"
+	Name          string                                 `json:"name"`
+	CreatedAt     string                                 `json:"created_at"`
+	UpdatedAt     string                                 `json:"updated_at"`
+	LastOperation *LastOperation                         `json:"last_operation"`
+	Relationships *ServiceCredentialBindingRelationships `json:"relationships"`
+	Metadata      *Metadata                              `json:"metadata"`
+	Links         map[string]*Link                       `json:"links"`
+}
+
+// ServiceCredentialBindingRelationships
+type ServiceCredentialBindingRelationships struct {
+	App             *Relationship `json:"app"`
+	ServiceInstance *Relationship `json:"service_instance"`
+}
+
+// LastOperation
+type LastOperation struct {
+	Type        string `json:"type"`  //"create"
+	State       string `json:"state"` //"succeeded"
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// AppGUID returns the guid of the bound app.
+func (b *ServiceCredentialBinding) AppGUID() string {
+
+	if b.Relationships == nil {
+		return ""
+	}
+
+	return b.Relationships.App.GUID()
+}
+
+// ServiceInstanceGUID returns the guid of the bound service instance.
+func (b *ServiceCredentialBinding) ServiceInstanceGUID() string {
+
+	if b.Relationships == nil {
+		return ""
+	}
+
+	return b.Relationships.ServiceInstance.GUID()
+}
+
+// ServiceInstance - A managed or user-provided service instance.
+type ServiceInstance struct {
+	GUID          string                        `json:"guid"`
+	Name          string                        `json:"name"` //"my-db"
+	Type          string                        `json:"type"` //"managed" or "user-provided"
+	Tags          []string                      `json:"tags"`
+	CreatedAt     string                        `json:"created_at"`
+	UpdatedAt     string                        `json:"updated_at"`
+	LastOperation *LastOperation                `json:"last_operation"`
+	Relationships *ServiceInstanceRelationships `json:"relationships"`
+	Metadata      *Metadata                     `json:"metadata"`
+	Links         map[string]*Link              `json:"links"`
+}
+
+// ServiceInstanceRelationships
+type ServiceInstanceRelationships struct {
+	Space       *Relationship `json:"space"`
+	ServicePlan *Relationship `json:"service_plan"`
+}
+
+// Managed reports whether the instance is provided by a service broker.
+func (i *ServiceInstance) Managed() bool {
+	return i.Type == "managed"
+}
+
+// SpaceGUID returns the guid of the space of the instance.
+func (i *ServiceInstance) SpaceGUID() string {
+
+	if i.Relationships == nil {
+		return ""
+	}
+
+	return i.Relationships.Space.GUID()
+}
+
+// ServicePlanGUID returns the guid of the plan of a managed instance.
+func (i *ServiceInstance) ServicePlanGUID() string {
+
+	if i.Relationships == nil {
+		return ""
+	}
+
+	return i.Relationships.ServicePlan.GUID()
+}
+
+// ServicePlan
+type ServicePlan struct {
+	GUID          string                    `json:"guid"`
+	Name          string                    `json:"name"` //"small"
+	Description   string                    `json:"description"`
+	Free          bool                      `json:"free"`
+	Available     bool                      `json:"available"`
+	CreatedAt     string                    `json:"created_at"`
+	UpdatedAt     string                    `json:"updated_at"`
+	Relationships *ServicePlanRelationships `json:"relationships"`
+	Metadata      *Metadata                 `json:"metadata"`
+	Links         map[string]*Link          `json:"links"`
+}
+
+// ServicePlanRelationships
+type ServicePlanRelationships struct {
+	ServiceOffering *Relationship `json:"service_offering"`
+}
+
+// ServiceOfferingGUID returns the guid of the offering of the plan.
+func (p *ServicePlan) ServiceOfferingGUID() string {
+
+	if p.Relationships == nil {
+		return ""
+	}
+
+	return p.Relationships.ServiceOffering.GUID()
+}
+
+// ServiceOffering
+type ServiceOffering struct {
+	GUID          string                        `json:"guid"`
+	Name          string                        `json:"name"` //"postgres"
+	Description   string                        `json:"description"`
+	Available     bool                          `json:"available"`
+	Tags          []string                      `json:"tags"`
+	Shareable     bool                          `json:"shareable"`
+	CreatedAt     string                        `json:"created_at"`
+	UpdatedAt     string                        `json:"updated_at"`
+	Relationships *ServiceOfferingRelationships `json:"relationships"`
+	Metadata      *Metadata                     `json:"metadata"`
+	Links         map[string]*Link              `json:"links"`
+}
+
+// ServiceOfferingRelationships
+type ServiceOfferingRelationships struct {
+	ServiceBroker *Relationship `json:"service_broker"`
+}
+
+// ServiceBrokerGUID returns the guid of the broker of the offering.
+func (o *ServiceOffering) ServiceBrokerGUID() string {
+
+	if o.Relationships == nil {
+		return ""
+	}
+
+	return o.Relationships.ServiceBroker.GUID()
+}
+
+// ServiceBroker
+type ServiceBroker struct {
+	GUID      string           `json:"guid"`
+	Name      string           `json:"name"` //"my-broker"
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+	Metadata  *Metadata        `json:"metadata"`
+	Links     map[string]*Link `json:"links"`
+}
@@ -0,0 +1,38 @@
+package v3
+
+// Space
+type Space struct {
+	GUID          string              `json:"guid"`
+	Name          string              `json:"name"`
+	CreatedAt     string              `json:"created_at"`
+	UpdatedAt     string              `json:"updated_at"`
+	Relationships *SpaceRelationships `json:"relationships"`
+	Metadata      *Metadata           `json:"metadata"`
+	Links         map[string]*Link    `json:"links"`
+}
+
+// SpaceRelationships
+type SpaceRelationships struct {
+	Organization *Relationship `json:"organization"`
+	Quota        *Relationship `json:"quota"`
+}
+
+// OrganizationGUID returns the guid of the organization of the space.
+func (s *Space) OrganizationGUID() string {
+
+	if s.Relationships == nil {
+		return ""
+	}
+
+	return s.Relationships.Organization.GUID()
+}
+
+// QuotaGUID returns the guid of the space quota or "" if none is assigned.
+func (s *Space) QuotaGUID() string {
+
+	if s.Relationships == nil {
+		return ""
+	}
+
+	return s.Relationships.Quota.GUID()
+}
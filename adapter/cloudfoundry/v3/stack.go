@@ -0,0 +1,15 @@
+package v3
+
+// Stack
+type Stack struct {
+	GUID             string           `json:"guid"`
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	BuildRootfsImage string           `json:"build_rootfs_image"`
+	RunRootfsImage   string           `json:"run_rootfs_image"`
+	Default          bool             `json:"default"`
+	CreatedAt        string           `json:"created_at"`
+	UpdatedAt        string           `json:"updated_at"`
+	Metadata         *Metadata        `json:"metadata"`
+	Links            map[string]*Link `json:"links"`
+}
@@ -0,0 +1,58 @@
+package v3
+
+// Sidecar - An additional process running in the containers of the app's
+// processes, e.g. an APM agent or a proxy.
+type Sidecar struct {
+	GUID          string                `json:"guid"`
+	Name          string                `json:"name"`    //"auth-sidecar"
+	Command       string                `json:"command"` //"bundle exec rackup"
+	ProcessTypes  []string              `json:"process_types"`
+	MemoryInMB    int                   `json:"memory_in_mb"`
+	Origin        string                `json:"origin"` //"user" or "buildpack"
+	CreatedAt     string                `json:"created_at"`
+	UpdatedAt     string                `json:"updated_at"`
+	Relationships *ProcessRelationships `json:"relationships"`
+}
+
+// AppGUID returns the guid of the app of the sidecar.
+func (s *Sidecar) AppGUID() string {
+
+	if s.Relationships == nil {
+		return ""
+	}
+
+	return s.Relationships.App.GUID()
+}
+
+// Task - A one-off process of an app.
+type Task struct {
+	GUID          string                `json:"guid"`
+	SequenceID    int                   `json:"sequence_id"`
+	Name          string                `json:"name"`    //"migrate"
+	Command       string                `json:"command"` //"rake db:migrate"
+	State         string                `json:"state"`   //"SUCCEEDED", "FAILED", "RUNNING", "PENDING" or "CANCELING"
+	MemoryInMB    int                   `json:"memory_in_mb"`
+	DiskInMB      int                   `json:"disk_in_mb"`
+	Result        *TaskResult           `json:"result"`
+	DropletGUID   string                `json:"droplet_guid"`
+	CreatedAt     string                `json:"created_at"`
+	UpdatedAt     string                `json:"updated_at"`
+	Relationships *ProcessRelationships `json:"relationships"`
+	Metadata      *Metadata             `json:"metadata"`
+	Links         map[string]*Link      `json:"links"`
+}
+
+// TaskResult
+type TaskResult struct {
+	FailureReason string `json:"failure_reason"`
+}
+
+// AppGUID returns the guid of the app of the task.
+func (t *Task) AppGUID() string {
+
+	if t.Relationships == nil {
+		return ""
+	}
+
+	return t.Relationships.App.GUID()
+}
@@ -1,317 +1,265 @@
 package plantuml
 
 import (
-	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
-	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"github.com/nrekretep/cloudpaint/domain/diagram"
+	"regexp"
 	"strings"
 )
 
+// PlantUML renders diagrams as plantuml source. It implements
+// diagram.Renderer.
 type PlantUML struct {
-	CloudController *cloudfoundry.CloudController
 }
 
-// CreateDiagram -
-func (p *PlantUML) CreateDiagram() string {
-	var stringBuilder strings.Builder
+// NewPlantUML -
+func NewPlantUML() *PlantUML {
 
-	p.WriteStartTag(&stringBuilder)
+	plantUML := &PlantUML{}
 
-	p.WriteAllStacks(&stringBuilder)
-	p.WriteAllBuildpacks(&stringBuilder)
-	p.WriteBuildpackStackRelation(&stringBuilder)
+	return plantUML
+}
 
-	p.WriteAllOrgs(&stringBuilder)
-	p.WriteAllSpaces(&stringBuilder)
-	p.WriteAllOrgSpaceRelations(&stringBuilder)
+// packageTypes are the node types whose groups are written as package.
+// Groups of other types are written as components containing components.
+var packageTypes = map[string]bool{
+	diagram.NodeOrganization: true,
+	diagram.NodeSpace:        true,
+}
 
-	p.WriteAllApps(&stringBuilder)
-	p.WriteSpaceAppRelation(&stringBuilder)
-	p.WriteAllAppBuildpackRelation(&stringBuilder)
-	p.WriteEndTag(&stringBuilder)
+// shortTypes are the node types written as [**name**] <<type>> as alias if
+// they have no attributes.
+var shortTypes = map[string]bool{
+	diagram.NodeOrganization:   true,
+	diagram.NodeSpace:          true,
+	diagram.NodeApp:            true,
+	diagram.NodeSharedDomain:   true,
+	diagram.NodePrivateDomain:  true,
+	diagram.NodeInternalDomain: true,
+	diagram.NodeBuildpack:      true,
+	diagram.NodeCNB:            true,
+	diagram.NodeStack:          true,
+}
 
-	return stringBuilder.String()
+// elementKinds maps the node types which are not written as component to
+// their plantuml element.
+var elementKinds = map[string]string{
+	diagram.NodeOrganizationQuota: "rectangle",
+	diagram.NodeSpaceQuota:        "rectangle",
+	diagram.NodeWarning:           "rectangle",
 }
 
-// CreateSingleAppDiagram -
-func (p *PlantUML) CreateSingleAppDiagram(app *v3.App) string {
+// Render returns the plantuml source of the diagram.
+func (p *PlantUML) Render(d *diagram.Diagram) string {
 	var stringBuilder strings.Builder
 
 	p.WriteStartTag(&stringBuilder)
-	p.WriteSkin(&stringBuilder)
-
-	p.WriteTitle(&stringBuilder, "Single App Diagram - "+app.Name)
-
-	space := (*p.CloudController.SpaceMap)[app.Relationships.Space.Data.GUID]
-	p.WriteSpace(&stringBuilder, space)
-
-	org := (*p.CloudController.OrganizationMap)[space.Entity.OrganizationGUID]
-	p.WriteOrg(&stringBuilder, org)
-
-	p.WriteOrgSpaceRelation(&stringBuilder, org.Metadata.GUID, space.Metadata.GUID)
-
-	p.WriteApp(&stringBuilder, app)
+	if !d.Compact {
+		p.WriteSkin(&stringBuilder)
+	}
 
-	if app.Lifecycle.Type == "buildpack" {
+	if d.Title != "" {
+		p.WriteTitle(&stringBuilder, d.Title)
+	}
 
-		for _, b := range app.Lifecycle.Data.Buildpacks {
-			p.WriteBuildpack(&stringBuilder, b)
-			p.WriteAppBuildpackRelation(&stringBuilder, app, b)
+	for _, element := range d.Elements {
+		switch e := element.(type) {
+		case *diagram.Node:
+			if d.Compact {
+				p.WriteCompactNode(&stringBuilder, e)
+			} else {
+				p.WriteNode(&stringBuilder, e)
+			}
+		case *diagram.Edge:
+			p.WriteEdge(&stringBuilder, e)
+		case *diagram.Break:
+			stringBuilder.WriteString("\n")
 		}
-
-		p.WriteStack(&stringBuilder, app.Lifecycle.Data.Stack)
-		p.WriteAppStackRelation(&stringBuilder, app, app.Lifecycle.Data.Stack)
 	}
 
-	p.WriteAppSpaceRelation(&stringBuilder, app)
-
 	p.WriteEndTag(&stringBuilder)
 
 	return stringBuilder.String()
 }
 
-// WriteAppStackRelation -
-func (p *PlantUML) WriteAppStackRelation(sb *strings.Builder, app *v3.App, s string) {
-
-	sb.WriteString(*p.TrimGUID(&app.GUID))
-	sb.WriteString(" --> ")
-	sb.WriteString(s)
-	sb.WriteString("\n")
-
-}
-
-// WriteStack -
-func (p *PlantUML) WriteStack(sb *strings.Builder, s string) {
-
-	sb.WriteString("[**")
-	sb.WriteString(s)
-	sb.WriteString("**] <<stack>> as ")
-	sb.WriteString(s)
-	sb.WriteString("\n")
-
-}
-
-// WriteAppBuildpackRelation -
-func (p *PlantUML) WriteAppBuildpackRelation(sb *strings.Builder, app *v3.App, b string) {
+// WriteNode writes the node with its children. Groups become packages or
+// components containing components, nodes without attributes of the
+// shortTypes are written in the short form.
+func (p *PlantUML) WriteNode(sb *strings.Builder, n *diagram.Node) {
 
-	sb.WriteString(*p.TrimGUID(&app.GUID))
-	sb.WriteString(" --> ")
-	sb.WriteString(b)
-	sb.WriteString("\n")
-
-}
-
-// WriteBuildpack -
-func (p *PlantUML) WriteBuildpack(sb *strings.Builder, b string) {
-
-	sb.WriteString("[**")
-	sb.WriteString(b)
-	sb.WriteString("**] <<buildpack>> as ")
-	sb.WriteString(b)
-	sb.WriteString("\n")
-
-}
-
-// WriteApp -
-func (p *PlantUML) WriteApp(sb *strings.Builder, app *v3.App) {
-
-	sb.WriteString("component ")
-	sb.WriteString(*p.TrimGUID(&app.GUID))
-	sb.WriteString(" <<app>> [\n**")
-	sb.WriteString(app.Name)
-	sb.WriteString("**\n")
-	sb.WriteString("State: " + app.State + "\n")
-	sb.WriteString("Created at: " + app.CreatedAt + "\n")
-	sb.WriteString("Updated at: " + app.UpdatedAt + "\n")
-	sb.WriteString("]")
-	sb.WriteString("\n")
-
-}
-
-// WriteAppSpaceRelation -
-func (p *PlantUML) WriteAppSpaceRelation(sb *strings.Builder, app *v3.App) {
+	alias := p.Alias(n.ID)
+	stereotypes := p.Stereotypes(n)
 
-	sb.WriteString(*p.TrimGUID(&app.Relationships.Space.Data.GUID))
-	sb.WriteString(" --> ")
-	sb.WriteString(*p.TrimGUID(&app.GUID))
-	sb.WriteString("\n")
+	color := ""
+	if n.Color != "" {
+		color = " " + n.Color
+	}
 
+	switch {
+	case n.Group || len(n.Children) > 0:
+		if packageTypes[n.Type] {
+			p.WritePackageStart(sb, alias, stereotypes, color, n.Name)
+		} else {
+			p.WriteContainerStart(sb, alias, stereotypes, color, n.Name, p.Lines(n))
+		}
+		for _, child := range n.Children {
+			p.WriteNode(sb, child)
+		}
+		p.WriteContainerEnd(sb)
+	case len(n.Attributes) == 0 && shortTypes[n.Type]:
+		sb.WriteString("[**")
+		sb.WriteString(n.Name)
+		sb.WriteString("**] ")
+		sb.WriteString(stereotypes)
+		sb.WriteString(" as ")
+		sb.WriteString(alias)
+		sb.WriteString(color)
+		sb.WriteString("\n")
+	default:
+		kind := elementKinds[n.Type]
+		if kind == "" {
+			kind = "component"
+		}
+		p.WriteElement(sb, kind, alias, stereotypes, color, n.Name, p.Lines(n))
+	}
 }
 
-// WriteOrgSpaceRelation -
-func (p *PlantUML) WriteOrgSpaceRelation(sb *strings.Builder, orgGUID string, spaceGUID string) {
-
-	sb.WriteString(*p.TrimGUID(&orgGUID))
-	sb.WriteString(" --> ")
-	sb.WriteString(*p.TrimGUID(&spaceGUID))
+// WriteCompactNode writes the node as [name] <<type>> as alias.
+func (p *PlantUML) WriteCompactNode(sb *strings.Builder, n *diagram.Node) {
+
+	sb.WriteString("[")
+	sb.WriteString(n.Name)
+	sb.WriteString("] ")
+	sb.WriteString(p.Stereotypes(n))
+	sb.WriteString(" as ")
+	sb.WriteString(p.Alias(n.ID))
+	if n.Color != "" {
+		sb.WriteString(" " + n.Color)
+	}
 	sb.WriteString("\n")
 
 }
 
-// WriteOrg -
-func (p *PlantUML) WriteOrg(sb *strings.Builder, org *cloudfoundry.OrganizationInfo) {
+// WriteEdge writes the edge as from --> to with an optional label. Network
+// policies are written as dotted arrows.
+func (p *PlantUML) WriteEdge(sb *strings.Builder, e *diagram.Edge) {
 
-	sb.WriteString("[**")
-	sb.WriteString(org.Entity.Name)
-	sb.WriteString("**] <<organization>> as ")
-	sb.WriteString(*p.TrimGUID(&org.Metadata.GUID))
-	sb.WriteString("\n")
-
-}
-
-// WriteSpace -
-func (p *PlantUML) WriteSpace(sb *strings.Builder, space *cloudfoundry.SpaceInfo) {
+	arrow := " --> "
+	if e.Type == diagram.EdgeNetworkPolicy {
+		arrow = " ..> "
+	}
 
-	sb.WriteString("[**")
-	sb.WriteString(space.Entity.Name)
-	sb.WriteString("**] <<space>> as ")
-	sb.WriteString(*p.TrimGUID(&space.Metadata.GUID))
+	sb.WriteString(p.Alias(e.From))
+	sb.WriteString(arrow)
+	sb.WriteString(p.Alias(e.To))
+	if e.Label != "" {
+		sb.WriteString(" : ")
+		sb.WriteString(e.Label)
+	}
 	sb.WriteString("\n")
 
 }
 
-// NewPlantUML -
-func NewPlantUML(c *cloudfoundry.CloudController) *PlantUML {
+// nonAliasCharacters are replaced in aliases, as ids like buildpack names or
+// git URLs may contain characters plantuml does not accept there.
+var nonAliasCharacters = regexp.MustCompile(`[^A-Za-z0-9_]+`)
 
-	plantUML := &PlantUML{CloudController: c}
-
-	return plantUML
+// Alias returns the plantuml alias of the node with the id. Dashes are
+// removed, other characters not allowed in aliases become "_".
+func (p *PlantUML) Alias(id string) string {
+	return nonAliasCharacters.ReplaceAllString(strings.Replace(id, "-", "", -1), "_")
 }
 
-// WriteAllStacks -
-func (p *PlantUML) WriteAllStacks(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.StackMap {
-
-		sb.WriteString("[")
-		sb.WriteString(v.Entity.Name)
-		sb.WriteString("] <<stack>> as ")
-		sb.WriteString(v.Entity.Name)
-		sb.WriteString("\n")
+// Stereotypes returns the type of the node followed by its tags as
+// stereotypes, e.g. "<<app>> <<frontend>>".
+func (p *PlantUML) Stereotypes(n *diagram.Node) string {
 
+	stereotypes := "<<" + n.Type + ">>"
+	for _, tag := range n.Tags {
+		stereotypes += " <<" + tag + ">>"
 	}
-	sb.WriteString("\n")
-}
-
-// WriteAllBuildpacks -
-func (p *PlantUML) WriteAllBuildpacks(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.BuildpackMap {
-
-		sb.WriteString("[")
-		sb.WriteString(v.Entity.Name)
-		sb.WriteString("] <<buildpack>> as ")
-		sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-		sb.WriteString("\n")
 
-	}
-	sb.WriteString("\n")
+	return stereotypes
 }
 
-// WriteBuildpackStackRelation -
-func (p *PlantUML) WriteBuildpackStackRelation(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.BuildpackMap {
+// Lines returns the attributes of the node as lines of description.
+// Warnings are written in red.
+func (p *PlantUML) Lines(n *diagram.Node) []string {
 
-		if v.Entity.Stack != "" {
-			sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-			sb.WriteString(" --> ")
-			sb.WriteString((*p.CloudController.StackMap)[v.Entity.Stack].Entity.Name)
-			sb.WriteString("\n")
+	var lines []string
+	for _, a := range n.Attributes {
+		if a.Warning {
+			lines = append(lines, "<color:red>"+a.String()+"</color>")
+			continue
 		}
+		lines = append(lines, a.String())
 	}
-	sb.WriteString("\n")
-}
-
-// WriteAllOrgs -
-func (p *PlantUML) WriteAllOrgs(sb *strings.Builder) {
 
-	for _, v := range *p.CloudController.OrganizationMap {
-
-		sb.WriteString("[")
-		sb.WriteString(v.Entity.Name)
-		sb.WriteString("] <<org>> as ")
-		sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-		sb.WriteString("\n")
-
-	}
-	sb.WriteString("\n")
+	return lines
 }
 
-// WriteAllSpaces -
-func (p *PlantUML) WriteAllSpaces(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.SpaceMap {
-
-		sb.WriteString("[")
-		sb.WriteString(v.Entity.Name)
-		sb.WriteString("] <<space>> as ")
-		sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-		sb.WriteString("\n")
-
+// WriteElement writes an element of the kind, e.g. component or rectangle,
+// with a bold title followed by further lines of description, e.g. state and
+// the configured labels.
+func (p *PlantUML) WriteElement(sb *strings.Builder, kind string, alias string, stereotypes string, color string, title string, lines []string) {
+
+	sb.WriteString(kind)
+	sb.WriteString(" ")
+	sb.WriteString(alias)
+	sb.WriteString(" ")
+	sb.WriteString(stereotypes)
+	sb.WriteString(color)
+	sb.WriteString(" [\n**")
+	sb.WriteString(title)
+	sb.WriteString("**\n")
+	for _, line := range lines {
+		sb.WriteString(line + "\n")
 	}
+	sb.WriteString("]")
 	sb.WriteString("\n")
 }
 
-// WriteAllOrgSpaceRelations -
-func (p *PlantUML) WriteAllOrgSpaceRelations(sb *strings.Builder) {
+// WriteContainerStart opens a component with a bold title and further lines
+// of description which contains other components. It must be closed with
+// WriteContainerEnd.
+func (p *PlantUML) WriteContainerStart(sb *strings.Builder, alias string, stereotypes string, color string, title string, lines []string) {
 
-	for _, v := range *p.CloudController.SpaceMap {
-
-		sb.WriteString(*p.TrimGUID(&(*p.CloudController.OrganizationMap)[v.Entity.OrganizationGUID].Metadata.GUID))
-		sb.WriteString(" --> ")
-		sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-		sb.WriteString("\n")
+	sb.WriteString("component \"**")
+	sb.WriteString(quote(title))
+	sb.WriteString("**")
+	for _, line := range lines {
+		sb.WriteString("\\n" + quote(line))
 	}
-	sb.WriteString("\n")
+	sb.WriteString("\" as ")
+	sb.WriteString(alias)
+	sb.WriteString(" ")
+	sb.WriteString(stereotypes)
+	sb.WriteString(color)
+	sb.WriteString(" {\n")
 }
 
-// WriteAllApps -
-func (p *PlantUML) WriteAllApps(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.AppMap {
-
-		sb.WriteString("[")
-		sb.WriteString(v.Entity.Name)
-		sb.WriteString("] <<app>> as ")
-		sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-		sb.WriteString("\n")
-
-	}
-	sb.WriteString("\n")
+// WritePackageStart opens a package which contains other elements. It must
+// be closed with WriteContainerEnd.
+func (p *PlantUML) WritePackageStart(sb *strings.Builder, alias string, stereotypes string, color string, title string) {
+
+	sb.WriteString("package \"")
+	sb.WriteString(quote(title))
+	sb.WriteString("\" as ")
+	sb.WriteString(alias)
+	sb.WriteString(" ")
+	sb.WriteString(stereotypes)
+	sb.WriteString(color)
+	sb.WriteString(" {\n")
 }
 
-// WriteSpaceAppRelation -
-func (p *PlantUML) WriteSpaceAppRelation(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.AppMap {
-
-		sb.WriteString(*p.TrimGUID(&(*p.CloudController.SpaceMap)[v.Entity.SpaceGUID].Metadata.GUID))
-		sb.WriteString(" --> ")
-		sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-		sb.WriteString("\n")
-	}
-	sb.WriteString("\n")
-}
-
-// WriteAppBuildpackRelation -
-func (p *PlantUML) WriteAllAppBuildpackRelation(sb *strings.Builder) {
-
-	for _, v := range *p.CloudController.AppMap {
-
-		if v.Entity.DetectedBuildpackGUID != "" {
-			sb.WriteString(*p.TrimGUID(&v.Metadata.GUID))
-			sb.WriteString(" --> ")
-			sb.WriteString(*p.TrimGUID(&(*p.CloudController.BuildpackMap)[v.Entity.DetectedBuildpackGUID].Metadata.GUID))
-		}
-
-		sb.WriteString("\n")
-	}
-	sb.WriteString("\n")
+// WriteContainerEnd closes a component opened with WriteContainerStart or a
+// package opened with WritePackageStart.
+func (p *PlantUML) WriteContainerEnd(sb *strings.Builder) {
+	sb.WriteString("}\n")
 }
 
-func (p *PlantUML) TrimGUID(guid *string) *string {
-	t := strings.Replace(*guid, "-", "", -1)
-	return &t
+// quote makes the text usable within a quoted plantuml name.
+func quote(text string) string {
+	return strings.Replace(text, "\"", "'", -1)
 }
 
 // WriteStartTag -
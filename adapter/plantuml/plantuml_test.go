@@ -0,0 +1,72 @@
+package plantuml
+
+import (
+	"github.com/nrekretep/cloudpaint/domain/diagram"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+
+	Convey("Given a diagram with groups, nodes and edges", t, func() {
+
+		d := diagram.New("Test Diagram")
+
+		space := &diagram.Node{ID: "space-guid", Type: diagram.NodeSpace, Name: "dev", Group: true, Tags: []string{"frontend"}, Color: "#LightBlue"}
+		d.AddNode(space)
+		app := (&diagram.Node{ID: "app-guid", Type: diagram.NodeApp, Name: "my-app"}).Attribute("State", "STARTED")
+		app.Children = []*diagram.Node{(&diagram.Node{ID: "web-guid", Type: diagram.NodeProcess, Name: "web"}).Attribute("Instances", "2")}
+		d.AddChild(space, app)
+
+		d.AddNode(&diagram.Node{ID: "java_buildpack", Type: diagram.NodeBuildpack, Name: "java_buildpack"})
+		d.AddEdge("app-guid", "java_buildpack", diagram.EdgeRuntime, "")
+		d.AddNode(&diagram.Node{ID: "https://github.com/cloudfoundry/go-buildpack.git#v1.2", Type: diagram.NodeBuildpack, Name: "https://github.com/cloudfoundry/go-buildpack.git#v1.2"})
+		d.AddEdge("app-guid", "https://github.com/cloudfoundry/go-buildpack.git#v1.2", diagram.EdgeRuntime, "")
+
+		group := &diagram.Node{ID: "sg-guid", Type: diagram.NodeSecurityGroup, Name: "public", Tags: []string{"overly broad"}, Color: "#Pink",
+			Attributes: []diagram.Attribute{{Value: "all 0.0.0.0/0 (overly broad)", Warning: true}}}
+		d.AddNode(group)
+		d.AddEdge("space-guid", "sg-guid", diagram.EdgeSecurityGroup, "running")
+
+		d.AddNode(&diagram.Node{ID: "quota-guid", Type: diagram.NodeSpaceQuota, Name: "small"})
+		d.AddEdge("app-guid", "peer-guid", diagram.EdgeNetworkPolicy, "tcp 8080")
+
+		Convey("When the diagram is rendered", func() {
+
+			source := NewPlantUML().Render(d)
+
+			Convey("Then groups are written as packages and containers", func() {
+				So(source, ShouldStartWith, "@startuml\n")
+				So(source, ShouldContainSubstring, "title Test Diagram\n")
+				So(source, ShouldContainSubstring, "package \"dev\" as spaceguid <<space>> <<frontend>> #LightBlue {\n"+
+					"component \"**my-app**\\nState: STARTED\" as appguid <<app>> {\n"+
+					"component webguid <<process>> [\n**web**\nInstances: 2\n]\n"+
+					"}\n"+
+					"}\n")
+			})
+
+			Convey("Then nodes without attributes are written in the short form", func() {
+				So(source, ShouldContainSubstring, "[**java_buildpack**] <<buildpack>> as java_buildpack\nappguid --> java_buildpack\n")
+			})
+
+			Convey("Then ids with characters not allowed in aliases are sanitized", func() {
+				So(source, ShouldContainSubstring, "[**https://github.com/cloudfoundry/go-buildpack.git#v1.2**] <<buildpack>> as https_github_com_cloudfoundry_gobuildpack_git_v1_2\n"+
+					"appguid --> https_github_com_cloudfoundry_gobuildpack_git_v1_2\n")
+			})
+
+			Convey("Then warnings are red and other element kinds are used", func() {
+				So(source, ShouldContainSubstring, "component sgguid <<security group>> <<overly broad>> #Pink [\n**public**\n<color:red>all 0.0.0.0/0 (overly broad)</color>\n]\n")
+				So(source, ShouldContainSubstring, "spaceguid --> sgguid : running\n")
+				So(source, ShouldContainSubstring, "rectangle quotaguid <<space quota>> [\n**small**\n]\n")
+			})
+
+			Convey("Then network policies are dotted edges", func() {
+				So(source, ShouldContainSubstring, "appguid ..> peerguid : tcp 8080\n")
+				So(source, ShouldEndWith, "@enduml\n")
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,190 @@
+package diagram
+
+import (
+	"errors"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// Builder creates diagrams from the resources provided by an Inventory.
+type Builder struct {
+	Inventory Inventory
+	Options   Options
+}
+
+// NewBuilder -
+func NewBuilder(inventory Inventory, options Options) *Builder {
+
+	builder := &Builder{Inventory: inventory, Options: options}
+
+	return builder
+}
+
+// FoundationDiagram creates a compact overview of all loaded stacks,
+// buildpacks, orgs, spaces and apps in sections of one kind each.
+func (b *Builder) FoundationDiagram() *Diagram {
+
+	d := New("")
+	d.Compact = true
+
+	stacks := b.Inventory.Stacks()
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].Name < stacks[j].Name })
+	for _, s := range stacks {
+		d.AddNode(b.Stack(s.Name))
+	}
+	d.AddBreak()
+
+	buildpacks := b.Inventory.Buildpacks()
+	sort.Slice(buildpacks, func(i, j int) bool { return buildpacks[i].Position < buildpacks[j].Position })
+	for _, bp := range buildpacks {
+		d.AddNode(b.Buildpack(bp.Name))
+	}
+	d.AddBreak()
+	for _, bp := range buildpacks {
+		if bp.Stack != "" {
+			d.AddEdge(bp.Name, bp.Stack, EdgeRuntime, "")
+		}
+	}
+	d.AddBreak()
+
+	orgs := b.Inventory.Organizations()
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+	for _, o := range orgs {
+		d.AddNode(b.Org(o))
+	}
+	d.AddBreak()
+
+	spaces := b.Inventory.Spaces()
+	sort.Slice(spaces, func(i, j int) bool { return spaces[i].Name < spaces[j].Name })
+	for _, s := range spaces {
+		d.AddNode(b.Space(s))
+	}
+	d.AddBreak()
+	for _, s := range spaces {
+		d.AddEdge(s.OrganizationGUID(), s.GUID, EdgeContains, "")
+	}
+	d.AddBreak()
+
+	apps := b.Inventory.Apps()
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+	for _, a := range apps {
+		d.AddNode(b.resource(a.GUID, NodeApp, a.Name, a.Metadata))
+	}
+	d.AddBreak()
+	for _, a := range apps {
+		if a.SpaceGUID() != "" {
+			d.AddEdge(a.SpaceGUID(), a.GUID, EdgeContains, "")
+		}
+	}
+	d.AddBreak()
+	for _, a := range apps {
+		for _, name := range a.Lifecycle.GetBuildpacks() {
+			if bp := b.FindBuildpack(name, a.Lifecycle.GetStack()); bp != nil {
+				d.AddEdge(a.GUID, bp.Name, EdgeRuntime, "")
+			}
+		}
+	}
+	d.AddBreak()
+
+	return d
+}
+
+// SingleAppDiagram creates the diagram of the app with its space, org,
+// processes, sidecars, tasks, lineage, routes, services, network policies
+// and runtime dependencies. An error is returned if the space or the org of
+// the app is not loaded.
+func (b *Builder) SingleAppDiagram(app *v3.App) (*Diagram, error) {
+
+	space := b.Inventory.Space(app.SpaceGUID())
+	if space == nil {
+		return nil, errors.New("space with id " + app.SpaceGUID() + " of app " + app.Name + " not found")
+	}
+
+	org := b.Inventory.Organization(space.OrganizationGUID())
+	if org == nil {
+		return nil, errors.New("organization with id " + space.OrganizationGUID() + " of space " + space.Name + " not found")
+	}
+
+	d := New("Single App Diagram - " + app.Name)
+
+	d.AddNode(b.Space(space))
+
+	d.AddNode(b.Org(org))
+
+	d.AddEdge(org.GUID, space.GUID, EdgeContains, "")
+
+	d.AddNode(b.App(app))
+
+	b.AddAppSidecars(d, app)
+
+	b.AddAppTasks(d, app)
+
+	b.AddAppLineage(d, app)
+
+	b.AddAppRoutes(d, app)
+
+	b.AddAppServices(d, app)
+
+	b.AddAppNetworkPolicies(d, app)
+
+	b.AddAppLifecycle(d, app)
+
+	d.AddEdge(space.GUID, app.GUID, EdgeContains, "")
+
+	return d, nil
+}
+
+// App returns the node of the app. The loaded processes of the app are its
+// children.
+func (b *Builder) App(app *v3.App) *Node {
+
+	n := b.resource(app.GUID, NodeApp, app.Name, app.Metadata).
+		Attribute("State", app.State).
+		Attribute("Created at", app.CreatedAt).
+		Attribute("Updated at", app.UpdatedAt)
+	n.Attributes = append(n.Attributes, b.MetadataAttributes(app.Metadata)...)
+
+	for _, process := range b.Inventory.ProcessesOf(app.GUID) {
+		n.Children = append(n.Children, b.Process(process))
+	}
+
+	return n
+}
+
+// Org returns the node of the organization.
+func (b *Builder) Org(org *v3.Organization) *Node {
+
+	n := b.resource(org.GUID, NodeOrganization, org.Name, org.Metadata)
+	n.Attributes = b.MetadataAttributes(org.Metadata)
+
+	return n
+}
+
+// Space returns the node of the space.
+func (b *Builder) Space(space *v3.Space) *Node {
+
+	n := b.resource(space.GUID, NodeSpace, space.Name, space.Metadata)
+	n.Attributes = b.MetadataAttributes(space.Metadata)
+
+	return n
+}
+
+// FindBuildpack returns the buildpack with the given name, preferring the one
+// for the given stack. Nil is returned for buildpacks given as git URL.
+func (b *Builder) FindBuildpack(name string, stack string) *v3.Buildpack {
+
+	var found *v3.Buildpack
+	for _, bp := range b.Inventory.Buildpacks() {
+		if bp.Name != name {
+			continue
+		}
+		if bp.Stack == stack {
+			return bp
+		}
+		if found == nil || bp.Stack == "" {
+			found = bp
+		}
+	}
+
+	return found
+}
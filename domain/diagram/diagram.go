@@ -0,0 +1,224 @@
+package diagram
+
+// Node types. They name the cloud foundry resource a node stands for.
+const (
+	NodeOrganization        = "organization"
+	NodeSpace               = "space"
+	NodeApp                 = "app"
+	NodeProcess             = "process"
+	NodeInstance            = "instance"
+	NodeSidecar             = "sidecar"
+	NodeTask                = "task"
+	NodeDroplet             = "droplet"
+	NodePackage             = "package"
+	NodeRevision            = "revision"
+	NodeRoute               = "route"
+	NodeSharedDomain        = "shared domain"
+	NodePrivateDomain       = "private domain"
+	NodeInternalDomain      = "internal domain"
+	NodeManagedService      = "managed service"
+	NodeUserProvidedService = "user-provided service"
+	NodeBuildpack           = "buildpack"
+	NodeCNB                 = "cnb"
+	NodeStack               = "stack"
+	NodeDockerImage         = "docker image"
+	NodeSecurityGroup       = "security group"
+	NodeOrganizationQuota   = "organization quota"
+	NodeSpaceQuota          = "space quota"
+	// NodeWarning tells the reader that the diagram is incomplete.
+	NodeWarning = "warning"
+)
+
+// EdgeType - The kind of relation between two nodes.
+type EdgeType string
+
+// Edge types.
+const (
+	// EdgeContains - organization to space and space to app.
+	EdgeContains EdgeType = "contains"
+	// EdgeRoute - domain to route and route to app.
+	EdgeRoute EdgeType = "route"
+	// EdgeBinding - app to bound service instance.
+	EdgeBinding EdgeType = "binding"
+	// EdgeNetworkPolicy - source app to destination app.
+	EdgeNetworkPolicy EdgeType = "network policy"
+	// EdgeRuntime - app to buildpack, stack or docker image and buildpack
+	// to stack.
+	EdgeRuntime EdgeType = "runtime"
+	// EdgeLineage - package to droplet to revision to app.
+	EdgeLineage EdgeType = "lineage"
+	// EdgeSidecar - process or app to sidecar.
+	EdgeSidecar EdgeType = "sidecar"
+	// EdgeTask - app to task.
+	EdgeTask EdgeType = "task"
+	// EdgeSecurityGroup - space to security group.
+	EdgeSecurityGroup EdgeType = "security group"
+)
+
+// Diagram - Nodes and edges of a diagram. The elements are kept in the
+// order they were added, so renderers produce stable output.
+type Diagram struct {
+	Title string
+	// Compact diagrams show nodes by name and type only, e.g. the overview
+	// of a whole foundation.
+	Compact  bool
+	Elements []Element
+
+	nodes map[string]*Node
+	edges map[Edge]bool
+}
+
+// Element is a *Node, an *Edge or a *Break.
+type Element interface {
+	element()
+}
+
+// Node - A resource like an app, a route or a buildpack. Nodes with
+// children are groups, e.g. an app containing its processes or a space
+// containing its apps.
+type Node struct {
+	// Group marks nodes which contain other nodes even if they have no
+	// children, e.g. an empty space.
+	Group bool
+	// ID is unique within the diagram, usually the guid of the resource.
+	ID   string
+	Type string
+	Name string
+	// Tags classify the node further, e.g. the current droplet or the value
+	// of a label.
+	Tags []string
+	// Color is the background colour, e.g. "#Pink", or "".
+	Color      string
+	Attributes []Attribute
+	Children   []*Node
+}
+
+// Attribute - A detail of a node like the state of an app. Attributes
+// without key are shown as value only.
+type Attribute struct {
+	Key   string
+	Value string
+	// Warning marks values which need attention, e.g. overly broad rules.
+	Warning bool
+}
+
+// Edge - A typed relation between the nodes with the ids From and To.
+type Edge struct {
+	From  string
+	To    string
+	Type  EdgeType
+	Label string
+}
+
+// Break - The end of a section of related elements, e.g. all stacks.
+type Break struct{}
+
+func (n *Node) element() {}
+
+func (e *Edge) element() {}
+
+func (b *Break) element() {}
+
+// String returns the attribute as "key: value" or value.
+func (a Attribute) String() string {
+
+	if a.Key == "" {
+		return a.Value
+	}
+
+	return a.Key + ": " + a.Value
+}
+
+// Attribute appends an attribute and returns the node.
+func (n *Node) Attribute(key string, value string) *Node {
+	n.Attributes = append(n.Attributes, Attribute{Key: key, Value: value})
+	return n
+}
+
+// New returns an empty diagram with the title.
+func New(title string) *Diagram {
+	return &Diagram{Title: title, nodes: make(map[string]*Node), edges: make(map[Edge]bool)}
+}
+
+// AddNode appends the node unless the diagram already contains a node with
+// the same id. It reports whether the node was added.
+func (d *Diagram) AddNode(n *Node) bool {
+
+	if !d.register(n) {
+		return false
+	}
+
+	d.Elements = append(d.Elements, n)
+	return true
+}
+
+// AddChild appends the node to the children of the group unless the diagram
+// already contains a node with the same id. It reports whether the node was
+// added.
+func (d *Diagram) AddChild(group *Node, n *Node) bool {
+
+	if !d.register(n) {
+		return false
+	}
+
+	group.Children = append(group.Children, n)
+	return true
+}
+
+// AddWarning appends a node telling the reader that parts of the diagram
+// are missing and why, e.g. network policies the user may not read.
+func (d *Diagram) AddWarning(id string, title string, reason string) {
+	d.AddNode(&Node{ID: id, Type: NodeWarning, Name: title, Color: "#Pink", Attributes: []Attribute{{Value: reason, Warning: true}}})
+}
+
+// AddEdge appends an edge between the nodes with the ids.
+func (d *Diagram) AddEdge(from string, to string, edgeType EdgeType, label string) {
+
+	e := Edge{From: from, To: to, Type: edgeType, Label: label}
+
+	if d.edges == nil {
+		d.edges = make(map[Edge]bool)
+	}
+	d.edges[e] = true
+
+	d.Elements = append(d.Elements, &e)
+}
+
+// AddBreak ends the current section of elements.
+func (d *Diagram) AddBreak() {
+	d.Elements = append(d.Elements, &Break{})
+}
+
+// HasEdge reports whether the diagram contains an edge equal to e.
+func (d *Diagram) HasEdge(e Edge) bool {
+	return d.edges[e]
+}
+
+// Contains reports whether the diagram contains a node with the id.
+func (d *Diagram) Contains(id string) bool {
+	return d.Node(id) != nil
+}
+
+// Node returns the node with the id or nil.
+func (d *Diagram) Node(id string) *Node {
+	return d.nodes[id]
+}
+
+// register indexes the node and its children by their ids.
+func (d *Diagram) register(n *Node) bool {
+
+	if d.nodes == nil {
+		d.nodes = make(map[string]*Node)
+	}
+
+	if d.nodes[n.ID] != nil {
+		return false
+	}
+
+	d.nodes[n.ID] = n
+	for _, child := range n.Children {
+		d.register(child)
+	}
+
+	return true
+}
@@ -0,0 +1,63 @@
+package diagram
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDiagram(t *testing.T) {
+
+	Convey("Given a diagram with a space group containing an app", t, func() {
+
+		d := New("Test")
+		space := &Node{ID: "space-guid", Type: NodeSpace, Name: "dev", Group: true}
+		d.AddNode(space)
+		app := (&Node{ID: "app-guid", Type: NodeApp, Name: "my-app"}).Attribute("State", "STARTED")
+		app.Children = []*Node{{ID: "process-guid", Type: NodeProcess, Name: "web"}}
+		d.AddChild(space, app)
+
+		Convey("When a node with the id of a child is added", func() {
+
+			added := d.AddNode(&Node{ID: "app-guid", Type: NodeApp, Name: "my-app"})
+
+			Convey("Then it is not added again", func() {
+				So(added, ShouldEqual, false)
+				So(len(d.Elements), ShouldEqual, 1)
+				So(d.Node("app-guid"), ShouldEqual, app)
+			})
+
+		})
+
+		Convey("When the children of nodes are looked up", func() {
+
+			Convey("Then the nested nodes are found", func() {
+				So(d.Contains("process-guid"), ShouldEqual, true)
+				So(d.Contains("unknown-guid"), ShouldEqual, false)
+			})
+
+		})
+
+		Convey("When an edge is added", func() {
+
+			d.AddEdge("app-guid", "other-app-guid", EdgeNetworkPolicy, "tcp 8080")
+
+			Convey("Then it is appended to the elements and can be found", func() {
+				So(d.Elements[len(d.Elements)-1], ShouldResemble, &Edge{From: "app-guid", To: "other-app-guid", Type: EdgeNetworkPolicy, Label: "tcp 8080"})
+				So(d.HasEdge(Edge{From: "app-guid", To: "other-app-guid", Type: EdgeNetworkPolicy, Label: "tcp 8080"}), ShouldEqual, true)
+				So(d.HasEdge(Edge{From: "other-app-guid", To: "app-guid", Type: EdgeNetworkPolicy, Label: "tcp 8080"}), ShouldEqual, false)
+			})
+
+		})
+
+		Convey("When the attributes are formatted", func() {
+
+			Convey("Then attributes without key show only the value", func() {
+				So(app.Attributes[0].String(), ShouldEqual, "State: STARTED")
+				So(Attribute{Value: "all 0.0.0.0/0"}.String(), ShouldEqual, "all 0.0.0.0/0")
+			})
+
+		})
+
+	})
+
+}
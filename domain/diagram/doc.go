@@ -0,0 +1,6 @@
+// Package diagram contains the renderer-agnostic model of the cloudpaint
+// diagrams. A Builder creates the nodes, edges and groups of a diagram once
+// from the resources of an Inventory, which the services provide from the
+// cloud controller adapter. A Renderer like the plantuml adapter turns the
+// diagram into an output format.
+package diagram
@@ -0,0 +1,45 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+)
+
+// Inventory provides the loaded resources of a foundation a Builder creates
+// diagrams from. Lookups return nil if the resource is not loaded.
+type Inventory interface {
+	Stacks() []*v3.Stack
+	Buildpacks() []*v3.Buildpack
+	Organizations() []*v3.Organization
+	Spaces() []*v3.Space
+	Apps() []*v3.App
+	Routes() []*v3.Route
+	ServiceCredentialBindings() []*v3.ServiceCredentialBinding
+	ServiceInstances() []*v3.ServiceInstance
+
+	Organization(guid string) *v3.Organization
+	Space(guid string) *v3.Space
+	App(guid string) *v3.App
+	OrganizationQuota(guid string) *v3.OrganizationQuota
+	SpaceQuota(guid string) *v3.SpaceQuota
+	Domain(guid string) *v3.Domain
+	ServiceInstance(guid string) *v3.ServiceInstance
+	ServicePlan(guid string) *v3.ServicePlan
+	ServiceOffering(guid string) *v3.ServiceOffering
+	ServiceBroker(guid string) *v3.ServiceBroker
+
+	ProcessesOf(appGUID string) []*v3.Process
+	ProcessStatsOf(processGUID string) []*v3.ProcessInstanceStats
+	SidecarsOf(appGUID string) []*v3.Sidecar
+	TasksOf(appGUID string) []*v3.Task
+	AppLineage(appGUID string) *v3.AppLineage
+	SecurityGroupsOf(spaceGUID string, lifecycle string) []*v3.SecurityGroup
+	NetworkPoliciesOf(appGUID string) []NetworkPolicy
+}
+
+// NetworkPolicy allows traffic from the source app to the destination app.
+type NetworkPolicy interface {
+	SourceGUID() string
+	DestinationGUID() string
+	// Description names protocol and ports, e.g. "tcp 8080".
+	Description() string
+}
@@ -0,0 +1,119 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"regexp"
+	"strings"
+)
+
+// AddAppLifecycle adds the runtime dependencies of the app depending on its
+// lifecycle: buildpacks and stack, cloud native buildpacks and stack or the
+// docker image. Dependencies are added once, even if they are shared by
+// several apps.
+func (b *Builder) AddAppLifecycle(d *Diagram, app *v3.App) {
+
+	switch app.Lifecycle.GetType() {
+	case v3.LifecycleBuildpack:
+		for _, bp := range app.Lifecycle.GetBuildpacks() {
+			d.AddNode(b.Buildpack(bp))
+			d.AddEdge(app.GUID, bp, EdgeRuntime, "")
+		}
+	case v3.LifecycleCNB:
+		for _, bp := range app.Lifecycle.GetBuildpacks() {
+			n := b.CNBBuildpack(bp)
+			d.AddNode(n)
+			d.AddEdge(app.GUID, n.ID, EdgeRuntime, "")
+		}
+	case v3.LifecycleDocker:
+		if image := b.DockerImage(app); image != "" {
+			n := b.DockerImageNode(image)
+			d.AddNode(n)
+			d.AddEdge(app.GUID, n.ID, EdgeRuntime, "")
+		}
+		return
+	}
+
+	if stack := app.Lifecycle.GetStack(); stack != "" {
+		d.AddNode(b.Stack(stack))
+		d.AddEdge(app.GUID, stack, EdgeRuntime, "")
+	}
+}
+
+// DockerImage returns the image of a docker app taken from its current
+// droplet or its newest package, or "" if the lineage is not loaded.
+func (b *Builder) DockerImage(app *v3.App) string {
+
+	lineage := b.AppLineage(app.GUID)
+	if lineage == nil {
+		return ""
+	}
+
+	if lineage.CurrentDroplet != nil && lineage.CurrentDroplet.Image != "" {
+		return lineage.CurrentDroplet.Image
+	}
+
+	for _, pkg := range lineage.Packages {
+		if pkg.Type == "docker" && pkg.Data != nil && pkg.Data.Image != "" {
+			return pkg.Data.Image
+		}
+	}
+
+	return ""
+}
+
+// Buildpack returns the node of a buildpack, identified by its name.
+func (b *Builder) Buildpack(name string) *Node {
+	return &Node{ID: name, Type: NodeBuildpack, Name: name}
+}
+
+// Stack returns the node of a stack, identified by its name.
+func (b *Builder) Stack(name string) *Node {
+	return &Node{ID: name, Type: NodeStack, Name: name}
+}
+
+// CNBBuildpack returns the node of a cloud native buildpack. Buildpacks
+// given as image reference show registry, repository and tag or digest.
+func (b *Builder) CNBBuildpack(buildpack string) *Node {
+
+	if !strings.Contains(buildpack, "://") {
+		return &Node{ID: cnbID(buildpack), Type: NodeCNB, Name: buildpack}
+	}
+
+	return imageReference(cnbID(buildpack), NodeCNB, buildpack)
+}
+
+// DockerImageNode returns the node of a docker image with its registry,
+// repository and tag or digest.
+func (b *Builder) DockerImageNode(image string) *Node {
+	return imageReference(imageID(image), NodeDockerImage, image)
+}
+
+// imageReference returns a node for the image with its registry,
+// repository and tag or digest.
+func imageReference(id string, nodeType string, image string) *Node {
+
+	ref := v3.ParseImageReference(image)
+
+	n := (&Node{ID: id, Type: nodeType, Name: ref.Repository}).
+		Attribute("Registry", ref.Registry)
+	if ref.Tag != "" {
+		n.Attribute("Tag", ref.Tag)
+	}
+	if ref.Digest != "" {
+		n.Attribute("Digest", ref.Digest)
+	}
+
+	return n
+}
+
+var nonIDCharacters = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// cnbID returns the id of a cloud native buildpack.
+func cnbID(buildpack string) string {
+	return "cnb_" + nonIDCharacters.ReplaceAllString(buildpack, "_")
+}
+
+// imageID returns the id of a docker image.
+func imageID(image string) string {
+	return "image_" + nonIDCharacters.ReplaceAllString(image, "_")
+}
@@ -0,0 +1,135 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strconv"
+)
+
+// AddAppLineage adds the loaded lineage of the app as
+// package --> droplet --> revision --> app. The currently running droplet
+// and staged droplets newer than it are tagged current and newer.
+func (b *Builder) AddAppLineage(d *Diagram, app *v3.App) {
+
+	lineage := b.AppLineage(app.GUID)
+	if lineage == nil {
+		return
+	}
+
+	var droplets []*v3.Droplet
+	writtenDroplets := make(map[string]bool)
+	addDroplet := func(droplet *v3.Droplet) {
+		if droplet != nil && !writtenDroplets[droplet.GUID] {
+			droplets = append(droplets, droplet)
+			writtenDroplets[droplet.GUID] = true
+		}
+	}
+	for _, r := range lineage.DeployedRevisions {
+		addDroplet(lineage.Droplet(r.DropletGUID()))
+	}
+	addDroplet(lineage.CurrentDroplet)
+	for _, droplet := range lineage.NewerDroplets() {
+		addDroplet(droplet)
+	}
+
+	for _, droplet := range droplets {
+		d.AddNode(b.Droplet(droplet, lineage))
+
+		pkg := lineage.Package(droplet.PackageGUID())
+		if pkg == nil {
+			continue
+		}
+		d.AddNode(b.Package(pkg))
+		d.AddEdge(pkg.GUID, droplet.GUID, EdgeLineage, "")
+	}
+
+	currentDeployed := false
+	for _, r := range lineage.DeployedRevisions {
+		d.AddNode(b.Revision(r))
+
+		if writtenDroplets[r.DropletGUID()] {
+			d.AddEdge(r.DropletGUID(), r.GUID, EdgeLineage, "")
+		}
+
+		label := ""
+		if lineage.CurrentDroplet != nil && r.DropletGUID() == lineage.CurrentDroplet.GUID {
+			label = "running"
+			currentDeployed = true
+		}
+		d.AddEdge(r.GUID, app.GUID, EdgeLineage, label)
+	}
+
+	if lineage.CurrentDroplet != nil && !currentDeployed {
+		d.AddEdge(lineage.CurrentDroplet.GUID, app.GUID, EdgeLineage, "running")
+	}
+}
+
+// AppLineage returns the loaded lineage of the app or nil.
+func (b *Builder) AppLineage(appGUID string) *v3.AppLineage {
+	return b.Inventory.AppLineage(appGUID)
+}
+
+// Droplet -
+func (b *Builder) Droplet(droplet *v3.Droplet, lineage *v3.AppLineage) *Node {
+
+	n := (&Node{ID: droplet.GUID, Type: NodeDroplet, Name: "droplet"}).
+		Attribute("State", droplet.State)
+	if droplet.Stack != "" {
+		n.Attribute("Stack", droplet.Stack)
+	}
+	for _, bp := range droplet.Buildpacks {
+		n.Attribute("Buildpack", bp.String())
+	}
+	if droplet.Image != "" {
+		n.Attribute("Image", droplet.Image)
+	}
+	if checksum := droplet.Checksum.String(); checksum != "" {
+		n.Attribute("Checksum", checksum)
+	}
+	n.Attribute("Staged at", droplet.CreatedAt)
+
+	if lineage.CurrentDroplet != nil && droplet.GUID == lineage.CurrentDroplet.GUID {
+		n.Tags = append(n.Tags, "current")
+		if newer := len(lineage.NewerDroplets()); newer > 0 {
+			n.Attribute("Newer staged droplets", strconv.Itoa(newer))
+		}
+	} else {
+		for _, newer := range lineage.NewerDroplets() {
+			if newer.GUID == droplet.GUID {
+				n.Tags = append(n.Tags, "newer")
+				n.Color = "#LightYellow"
+			}
+		}
+	}
+
+	return n
+}
+
+// Package -
+func (b *Builder) Package(pkg *v3.Package) *Node {
+
+	n := (&Node{ID: pkg.GUID, Type: NodePackage, Name: pkg.Type + " package"}).
+		Attribute("State", pkg.State)
+	if pkg.Data != nil {
+		if checksum := pkg.Data.Checksum.String(); checksum != "" {
+			n.Attribute("Checksum", checksum)
+		}
+		if pkg.Data.Image != "" {
+			n.Attribute("Image", pkg.Data.Image)
+		}
+	}
+	n.Attribute("Created at", pkg.CreatedAt)
+
+	return n
+}
+
+// Revision -
+func (b *Builder) Revision(revision *v3.Revision) *Node {
+
+	n := &Node{ID: revision.GUID, Type: NodeRevision, Name: "revision " + strconv.Itoa(revision.Version)}
+	if revision.Description != "" {
+		n.Attribute("Description", revision.Description)
+	}
+	n.Attribute("Created at", revision.CreatedAt)
+
+	return n
+}
@@ -0,0 +1,49 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+)
+
+// MultiAppDiagram creates the diagram of apps of several spaces. The apps
+// are grouped by their organizations and spaces, their routes, service
+// instances, network policies and runtime dependencies are added once and
+// shared by all apps using them.
+func (b *Builder) MultiAppDiagram(title string, orgs []*v3.Organization, spaces []*v3.Space, apps []*v3.App) *Diagram {
+
+	d := New(title)
+
+	for _, org := range orgs {
+		orgNode := b.resource(org.GUID, NodeOrganization, org.Name, org.Metadata)
+		orgNode.Group = true
+		d.AddNode(orgNode)
+
+		for _, space := range spaces {
+			if space.OrganizationGUID() != org.GUID {
+				continue
+			}
+
+			spaceNode := b.resource(space.GUID, NodeSpace, space.Name, space.Metadata)
+			spaceNode.Group = true
+			d.AddChild(orgNode, spaceNode)
+			for _, app := range apps {
+				if app.SpaceGUID() == space.GUID {
+					d.AddChild(spaceNode, b.App(app))
+				}
+			}
+		}
+	}
+
+	// apps of spaces which are not loaded are added without group
+	for _, app := range apps {
+		d.AddNode(b.App(app))
+	}
+
+	for _, app := range apps {
+		b.AddAppRoutes(d, app)
+		b.AddAppServices(d, app)
+		b.AddAppNetworkPolicies(d, app)
+		b.AddAppLifecycle(d, app)
+	}
+
+	return d
+}
@@ -0,0 +1,56 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+)
+
+// NetworkPolicyDiagram creates the diagram of the apps together with all
+// network policies in which they are source or destination. Apps outside
+// of the given ones are added when they are peer of a policy.
+func (b *Builder) NetworkPolicyDiagram(title string, apps []*v3.App) *Diagram {
+
+	d := New(title)
+
+	for _, app := range apps {
+		d.AddNode(b.App(app))
+	}
+
+	for _, app := range apps {
+		b.AddAppNetworkPolicies(d, app)
+	}
+
+	return d
+}
+
+// AddAppNetworkPolicies adds the inbound and outbound network policies of
+// the app as source --> destination. Peer apps and policies are added once.
+func (b *Builder) AddAppNetworkPolicies(d *Diagram, app *v3.App) {
+
+	for _, policy := range b.Inventory.NetworkPoliciesOf(app.GUID) {
+
+		edge := Edge{From: policy.SourceGUID(), To: policy.DestinationGUID(), Type: EdgeNetworkPolicy, Label: policy.Description()}
+		if d.HasEdge(edge) {
+			continue
+		}
+
+		for _, guid := range []string{policy.SourceGUID(), policy.DestinationGUID()} {
+			if !d.Contains(guid) {
+				d.AddNode(b.PeerApp(guid))
+			}
+		}
+
+		d.AddEdge(edge.From, edge.To, edge.Type, edge.Label)
+	}
+}
+
+// PeerApp returns the node of an app which is only part of the diagram
+// because of a network policy. Apps which are not loaded are shown with
+// their guid.
+func (b *Builder) PeerApp(guid string) *Node {
+
+	if app := b.Inventory.App(guid); app != nil {
+		return b.App(app)
+	}
+
+	return &Node{ID: guid, Type: NodeApp, Name: guid}
+}
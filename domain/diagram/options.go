@@ -0,0 +1,92 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strings"
+)
+
+// Options - Settings which control how the cloud foundry metadata of apps,
+// spaces and orgs is shown in the diagrams.
+type Options struct {
+	// LabelKeys are the labels shown inside app, space and org components.
+	LabelKeys []string
+	// AnnotationKeys are the annotations shown inside app, space and org
+	// components.
+	AnnotationKeys []string
+	// StereotypeLabel is a label whose value is added as tag, e.g. with
+	// "tier" the label tier=frontend becomes the tag frontend.
+	StereotypeLabel string
+	// ColorLabel is a label whose value selects the background colour of a
+	// component from LabelColors, e.g. {"frontend": "#cdffeb"}.
+	ColorLabel  string
+	LabelColors map[string]string
+	// InstanceDetails shows every instance of a process with its state,
+	// uptime, cpu and memory usage.
+	InstanceDetails bool
+}
+
+// MetadataAttributes returns the configured labels and annotations which
+// are set in the metadata.
+func (b *Builder) MetadataAttributes(m *v3.Metadata) []Attribute {
+
+	var attributes []Attribute
+
+	for _, key := range b.Options.LabelKeys {
+		if value, ok := m.Label(key); ok {
+			attributes = append(attributes, Attribute{Key: key, Value: value})
+		}
+	}
+
+	for _, key := range b.Options.AnnotationKeys {
+		if value, ok := m.Annotation(key); ok {
+			attributes = append(attributes, Attribute{Key: key, Value: value})
+		}
+	}
+
+	return attributes
+}
+
+// Tags returns the value of the StereotypeLabel as tag, if it is set.
+func (b *Builder) Tags(m *v3.Metadata) []string {
+
+	if b.Options.StereotypeLabel == "" {
+		return nil
+	}
+
+	value, ok := m.Label(b.Options.StereotypeLabel)
+	if !ok || value == "" {
+		return nil
+	}
+
+	return []string{value}
+}
+
+// Color returns the colour for the value of the ColorLabel, or "" if no
+// colour is configured for the resource.
+func (b *Builder) Color(m *v3.Metadata) string {
+
+	if b.Options.ColorLabel == "" {
+		return ""
+	}
+
+	value, ok := m.Label(b.Options.ColorLabel)
+	if !ok {
+		return ""
+	}
+
+	color, ok := b.Options.LabelColors[value]
+	if !ok || color == "" {
+		return ""
+	}
+
+	if !strings.HasPrefix(color, "#") {
+		color = "#" + color
+	}
+
+	return color
+}
+
+// resource returns a node for a resource with metadata.
+func (b *Builder) resource(id string, nodeType string, name string, m *v3.Metadata) *Node {
+	return &Node{ID: id, Type: nodeType, Name: name, Tags: b.Tags(m), Color: b.Color(m)}
+}
@@ -0,0 +1,98 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strconv"
+)
+
+// OrgDiagram creates the diagram of the organization as group containing
+// its spaces as groups, which contain their apps. The organization quota is
+// placed in the organization and the space quotas in their spaces.
+func (b *Builder) OrgDiagram(org *v3.Organization, spaces []*v3.Space, apps []*v3.App) *Diagram {
+
+	d := New("Organization Diagram - " + org.Name)
+
+	orgNode := b.resource(org.GUID, NodeOrganization, org.Name, org.Metadata)
+	orgNode.Group = true
+	d.AddNode(orgNode)
+
+	if quota := b.OrganizationQuota(org.QuotaGUID()); quota != nil {
+		n := &Node{ID: quota.GUID, Type: NodeOrganizationQuota, Name: quota.Name}
+		n.Attributes = quotaAttributes(quota.Apps, quota.Services, quota.Routes)
+		if quota.Domains != nil {
+			n.Attribute("Domains", limit(quota.Domains.TotalDomains, ""))
+		}
+		d.AddChild(orgNode, n)
+	}
+
+	for _, space := range spaces {
+		spaceNode := b.resource(space.GUID, NodeSpace, space.Name, space.Metadata)
+		spaceNode.Group = true
+		d.AddChild(orgNode, spaceNode)
+
+		if quota := b.SpaceQuota(space.QuotaGUID()); quota != nil {
+			// space quotas are shared by spaces, so the id is made unique per space
+			n := &Node{ID: quota.GUID + "_" + space.GUID, Type: NodeSpaceQuota, Name: quota.Name}
+			n.Attributes = quotaAttributes(quota.Apps, quota.Services, quota.Routes)
+			d.AddChild(spaceNode, n)
+		}
+
+		for _, app := range apps {
+			if app.SpaceGUID() == space.GUID {
+				d.AddChild(spaceNode, b.App(app))
+			}
+		}
+	}
+
+	return d
+}
+
+// OrganizationQuota returns the loaded organization quota with the guid or
+// nil.
+func (b *Builder) OrganizationQuota(guid string) *v3.OrganizationQuota {
+
+	return b.Inventory.OrganizationQuota(guid)
+}
+
+// SpaceQuota returns the loaded space quota with the guid or nil.
+func (b *Builder) SpaceQuota(guid string) *v3.SpaceQuota {
+
+	return b.Inventory.SpaceQuota(guid)
+}
+
+// quotaAttributes returns the limits of a quota.
+func quotaAttributes(apps *v3.QuotaApps, services *v3.QuotaServices, routes *v3.QuotaRoutes) []Attribute {
+
+	var attributes []Attribute
+
+	if apps != nil {
+		attributes = append(attributes,
+			Attribute{Key: "Total memory", Value: limit(apps.TotalMemoryInMB, " MB")},
+			Attribute{Key: "Memory per process", Value: limit(apps.PerProcessMemoryInMB, " MB")},
+			Attribute{Key: "Instances", Value: limit(apps.TotalInstances, "")},
+		)
+	}
+
+	if services != nil {
+		attributes = append(attributes,
+			Attribute{Key: "Service instances", Value: limit(services.TotalServiceInstances, "")},
+			Attribute{Key: "Paid services", Value: strconv.FormatBool(services.PaidServicesAllowed)},
+		)
+	}
+
+	if routes != nil {
+		attributes = append(attributes, Attribute{Key: "Routes", Value: limit(routes.TotalRoutes, "")})
+	}
+
+	return attributes
+}
+
+// limit returns the limit with its unit or "unlimited" for nil limits.
+func limit(value *int, unit string) string {
+
+	if value == nil {
+		return "unlimited"
+	}
+
+	return strconv.Itoa(*value) + unit
+}
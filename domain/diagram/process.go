@@ -0,0 +1,61 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strconv"
+)
+
+// Process returns the node of the process with its instance count,
+// resources, health check and command. With Options.InstanceDetails the
+// instances are its children.
+func (b *Builder) Process(process *v3.Process) *Node {
+
+	stats := b.Inventory.ProcessStatsOf(process.GUID)
+
+	instances := strconv.Itoa(process.Instances)
+	if stats != nil {
+		running := 0
+		for _, s := range stats {
+			if s.Running() {
+				running++
+			}
+		}
+		instances = strconv.Itoa(running) + "/" + strconv.Itoa(process.Instances) + " running"
+	}
+
+	n := (&Node{ID: process.GUID, Type: NodeProcess, Name: process.Type}).
+		Attribute("Instances", instances).
+		Attribute("Memory", strconv.Itoa(process.MemoryInMB)+" MB").
+		Attribute("Disk", strconv.Itoa(process.DiskInMB)+" MB")
+	if process.HealthCheck != nil {
+		n.Attribute("Health check", process.HealthCheck.String())
+	}
+	if process.Command != "" {
+		n.Attribute("Command", process.Command)
+	}
+
+	if b.Options.InstanceDetails {
+		for _, s := range stats {
+			n.Children = append(n.Children, b.ProcessInstance(process, s))
+		}
+	}
+
+	return n
+}
+
+// ProcessInstance returns the node of an instance of the process.
+func (b *Builder) ProcessInstance(process *v3.Process, stats *v3.ProcessInstanceStats) *Node {
+
+	index := strconv.Itoa(stats.Index)
+
+	n := (&Node{ID: process.GUID + "_" + index, Type: NodeInstance, Name: "#" + index}).
+		Attribute("State", stats.State)
+	if stats.Running() {
+		n.Attribute("Uptime", stats.UptimeString())
+	}
+	if stats.Usage != nil {
+		n.Attribute("CPU", stats.CPUString()).Attribute("Memory", stats.MemoryString())
+	}
+
+	return n
+}
@@ -0,0 +1,7 @@
+package diagram
+
+// Renderer turns a diagram into the source of an output format, e.g.
+// plantuml.
+type Renderer interface {
+	Render(d *Diagram) string
+}
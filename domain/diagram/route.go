@@ -0,0 +1,79 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+	"strconv"
+)
+
+// AddAppRoutes adds the routes of the app together with their domains as
+// domain --> route --> app. Domains and routes are added once, even if
+// they are shared by several apps.
+func (b *Builder) AddAppRoutes(d *Diagram, app *v3.App) {
+
+	for _, route := range b.AppRoutes(app.GUID) {
+
+		domain := b.Domain(route.DomainGUID())
+		if domain != nil {
+			d.AddNode(b.DomainNode(domain))
+		}
+
+		if d.AddNode(b.Route(route)) && domain != nil {
+			d.AddEdge(domain.GUID, route.GUID, EdgeRoute, "")
+		}
+
+		for _, destination := range route.DestinationsOf(app.GUID) {
+			d.AddEdge(route.GUID, app.GUID, EdgeRoute, destination.Description())
+		}
+	}
+}
+
+// AppRoutes returns the loaded routes with a destination pointing to the
+// app, sorted by URL.
+func (b *Builder) AppRoutes(appGUID string) []*v3.Route {
+
+	var routes []*v3.Route
+	for _, route := range b.Inventory.Routes() {
+		if len(route.DestinationsOf(appGUID)) > 0 {
+			routes = append(routes, route)
+		}
+	}
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].URL < routes[j].URL })
+	return routes
+}
+
+// Domain returns the loaded domain with the guid or nil.
+func (b *Builder) Domain(guid string) *v3.Domain {
+
+	return b.Inventory.Domain(guid)
+}
+
+// DomainNode returns the node of the domain, whose type tells whether it
+// is internal, shared or private.
+func (b *Builder) DomainNode(domain *v3.Domain) *Node {
+
+	nodeType := NodePrivateDomain
+	if domain.Internal {
+		nodeType = NodeInternalDomain
+	} else if domain.Shared() {
+		nodeType = NodeSharedDomain
+	}
+
+	return &Node{ID: domain.GUID, Type: nodeType, Name: domain.Name}
+}
+
+// Route -
+func (b *Builder) Route(route *v3.Route) *Node {
+
+	n := (&Node{ID: route.GUID, Type: NodeRoute, Name: route.URL}).
+		Attribute("Protocol", route.Protocol)
+	if route.Path != "" {
+		n.Attribute("Path", route.Path)
+	}
+	if route.Port != nil {
+		n.Attribute("Port", strconv.Itoa(*route.Port))
+	}
+
+	return n
+}
@@ -0,0 +1,83 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strings"
+)
+
+// SecurityGroupDiagram creates the diagram of the spaces with their apps
+// and the running and staging security groups which apply to them. Overly
+// broad groups and rules are marked.
+func (b *Builder) SecurityGroupDiagram(title string, spaces []*v3.Space, apps []*v3.App) *Diagram {
+
+	d := New(title)
+
+	for _, space := range spaces {
+		d.AddNode(b.Space(space))
+
+		for _, app := range apps {
+			if app.SpaceGUID() == space.GUID {
+				d.AddNode(b.App(app))
+				d.AddEdge(space.GUID, app.GUID, EdgeContains, "")
+			}
+		}
+
+		b.AddSpaceSecurityGroups(d, space)
+	}
+
+	return d
+}
+
+// AddSpaceSecurityGroups adds the security groups applying to the space
+// together with space --> group edges labelled with the lifecycles. Groups
+// are added once, even if they apply to several spaces.
+func (b *Builder) AddSpaceSecurityGroups(d *Diagram, space *v3.Space) {
+
+	var groups []*v3.SecurityGroup
+	lifecycles := make(map[string][]string)
+	for _, lifecycle := range []string{v3.SecurityGroupRunning, v3.SecurityGroupStaging} {
+		for _, group := range b.Inventory.SecurityGroupsOf(space.GUID, lifecycle) {
+			if lifecycles[group.GUID] == nil {
+				groups = append(groups, group)
+			}
+			lifecycles[group.GUID] = append(lifecycles[group.GUID], lifecycle)
+		}
+	}
+
+	for _, group := range groups {
+		d.AddNode(b.SecurityGroup(group))
+		d.AddEdge(space.GUID, group.GUID, EdgeSecurityGroup, strings.Join(lifecycles[group.GUID], ", "))
+	}
+}
+
+// SecurityGroup returns the node of the security group with its rules.
+// Overly broad groups are tagged and their broad rules are warnings.
+func (b *Builder) SecurityGroup(group *v3.SecurityGroup) *Node {
+
+	n := &Node{ID: group.GUID, Type: NodeSecurityGroup, Name: group.Name}
+
+	var global []string
+	for _, lifecycle := range []string{v3.SecurityGroupRunning, v3.SecurityGroupStaging} {
+		if group.Global(lifecycle) {
+			global = append(global, lifecycle)
+		}
+	}
+	if len(global) > 0 {
+		n.Attribute("Global", strings.Join(global, ", "))
+	}
+
+	for _, rule := range group.Rules {
+		if rule.Broad() {
+			n.Attributes = append(n.Attributes, Attribute{Value: rule.String() + " (overly broad)", Warning: true})
+			continue
+		}
+		n.Attributes = append(n.Attributes, Attribute{Value: rule.String()})
+	}
+
+	if group.Broad() {
+		n.Tags = append(n.Tags, "overly broad")
+		n.Color = "#Pink"
+	}
+
+	return n
+}
@@ -0,0 +1,103 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// AddAppServices adds the service instances bound to the app as
+// app --> service instance. Instances are added once, even if they are
+// bound to several apps. Credentials are never part of the diagram.
+func (b *Builder) AddAppServices(d *Diagram, app *v3.App) {
+
+	for _, binding := range b.AppServiceBindings(app.GUID) {
+
+		instance := b.ServiceInstance(binding.ServiceInstanceGUID())
+		if instance == nil {
+			continue
+		}
+
+		d.AddNode(b.ServiceInstanceNode(instance))
+		d.AddEdge(app.GUID, instance.GUID, EdgeBinding, binding.Name)
+	}
+}
+
+// AppServiceBindings returns the loaded app bindings of the app, sorted by
+// the name of the bound service instance.
+func (b *Builder) AppServiceBindings(appGUID string) []*v3.ServiceCredentialBinding {
+
+	var bindings []*v3.ServiceCredentialBinding
+	for _, binding := range b.Inventory.ServiceCredentialBindings() {
+		if binding.Type == "app" && binding.AppGUID() == appGUID {
+			bindings = append(bindings, binding)
+		}
+	}
+
+	name := func(binding *v3.ServiceCredentialBinding) string {
+		if instance := b.ServiceInstance(binding.ServiceInstanceGUID()); instance != nil {
+			return instance.Name
+		}
+		return ""
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		if name(bindings[i]) != name(bindings[j]) {
+			return name(bindings[i]) < name(bindings[j])
+		}
+		return bindings[i].GUID < bindings[j].GUID
+	})
+
+	return bindings
+}
+
+// ServiceInstance returns the loaded service instance with the guid or nil.
+func (b *Builder) ServiceInstance(guid string) *v3.ServiceInstance {
+
+	return b.Inventory.ServiceInstance(guid)
+}
+
+// ServiceInstanceAttributes returns the offering, plan and broker of a
+// managed service instance as far as they are loaded.
+func (b *Builder) ServiceInstanceAttributes(instance *v3.ServiceInstance) []Attribute {
+
+	var attributes []Attribute
+	if !instance.Managed() {
+		return attributes
+	}
+
+	plan := b.Inventory.ServicePlan(instance.ServicePlanGUID())
+	if plan == nil {
+		return attributes
+	}
+
+	offering := b.Inventory.ServiceOffering(plan.ServiceOfferingGUID())
+	if offering != nil {
+		attributes = append(attributes, Attribute{Key: "Offering", Value: offering.Name})
+	}
+
+	attributes = append(attributes, Attribute{Key: "Plan", Value: plan.Name})
+
+	if offering == nil {
+		return attributes
+	}
+
+	if broker := b.Inventory.ServiceBroker(offering.ServiceBrokerGUID()); broker != nil {
+		attributes = append(attributes, Attribute{Key: "Broker", Value: broker.Name})
+	}
+
+	return attributes
+}
+
+// ServiceInstanceNode returns the node of a managed or user-provided
+// service instance.
+func (b *Builder) ServiceInstanceNode(instance *v3.ServiceInstance) *Node {
+
+	nodeType := NodeUserProvidedService
+	if instance.Managed() {
+		nodeType = NodeManagedService
+	}
+
+	n := b.resource(instance.GUID, nodeType, instance.Name, instance.Metadata)
+	n.Attributes = append(b.ServiceInstanceAttributes(instance), b.MetadataAttributes(instance.Metadata)...)
+
+	return n
+}
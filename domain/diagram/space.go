@@ -0,0 +1,51 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// SpaceDiagram creates the diagram of the apps of the space together with
+// their routes, service instances, network policies and runtime
+// dependencies. Unbound service instances of the space are added as well.
+func (b *Builder) SpaceDiagram(org *v3.Organization, space *v3.Space, apps []*v3.App) *Diagram {
+
+	d := New("Space Diagram - " + org.Name + " / " + space.Name)
+
+	d.AddNode(b.Org(org))
+	d.AddNode(b.Space(space))
+	d.AddEdge(org.GUID, space.GUID, EdgeContains, "")
+
+	for _, app := range apps {
+		d.AddNode(b.App(app))
+		d.AddEdge(space.GUID, app.GUID, EdgeContains, "")
+	}
+
+	for _, app := range apps {
+		b.AddAppRoutes(d, app)
+		b.AddAppServices(d, app)
+		b.AddAppNetworkPolicies(d, app)
+		b.AddAppLifecycle(d, app)
+	}
+
+	for _, instance := range b.SpaceServiceInstances(space.GUID) {
+		d.AddNode(b.ServiceInstanceNode(instance))
+	}
+
+	return d
+}
+
+// SpaceServiceInstances returns the loaded service instances of the space
+// sorted by name.
+func (b *Builder) SpaceServiceInstances(spaceGUID string) []*v3.ServiceInstance {
+
+	var instances []*v3.ServiceInstance
+	for _, instance := range b.Inventory.ServiceInstances() {
+		if instance.SpaceGUID() == spaceGUID {
+			instances = append(instances, instance)
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+	return instances
+}
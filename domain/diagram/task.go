@@ -0,0 +1,82 @@
+package diagram
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"strconv"
+	"strings"
+)
+
+// AddAppSidecars adds the loaded sidecars of the app. Each sidecar is
+// attached to the processes it runs with, or to the app if none of them
+// are loaded.
+func (b *Builder) AddAppSidecars(d *Diagram, app *v3.App) {
+
+	processes := make(map[string]*v3.Process)
+	for _, process := range b.Inventory.ProcessesOf(app.GUID) {
+		processes[process.Type] = process
+	}
+
+	for _, sidecar := range b.Inventory.SidecarsOf(app.GUID) {
+		d.AddNode(b.Sidecar(sidecar))
+
+		attached := false
+		for _, processType := range sidecar.ProcessTypes {
+			if process := processes[processType]; process != nil {
+				d.AddEdge(process.GUID, sidecar.GUID, EdgeSidecar, "sidecar")
+				attached = true
+			}
+		}
+
+		if !attached {
+			d.AddEdge(app.GUID, sidecar.GUID, EdgeSidecar, "sidecar")
+		}
+	}
+}
+
+// Sidecar -
+func (b *Builder) Sidecar(sidecar *v3.Sidecar) *Node {
+
+	n := (&Node{ID: sidecar.GUID, Type: NodeSidecar, Name: sidecar.Name}).
+		Attribute("Command", sidecar.Command)
+	if len(sidecar.ProcessTypes) > 0 {
+		n.Attribute("Process types", strings.Join(sidecar.ProcessTypes, ", "))
+	}
+	if sidecar.MemoryInMB > 0 {
+		n.Attribute("Memory", strconv.Itoa(sidecar.MemoryInMB)+" MB")
+	}
+	if sidecar.Origin != "" {
+		n.Attribute("Origin", sidecar.Origin)
+	}
+
+	return n
+}
+
+// AddAppTasks adds the loaded recent tasks of the app.
+func (b *Builder) AddAppTasks(d *Diagram, app *v3.App) {
+
+	for _, task := range b.Inventory.TasksOf(app.GUID) {
+		d.AddNode(b.Task(task))
+		d.AddEdge(app.GUID, task.GUID, EdgeTask, "task")
+	}
+}
+
+// Task -
+func (b *Builder) Task(task *v3.Task) *Node {
+
+	n := (&Node{ID: task.GUID, Type: NodeTask, Name: task.Name}).
+		Attribute("State", task.State)
+	if task.Result != nil && task.Result.FailureReason != "" {
+		n.Attribute("Failure reason", task.Result.FailureReason)
+	}
+	if task.Command != "" {
+		n.Attribute("Command", task.Command)
+	}
+	n.Attribute("Memory", strconv.Itoa(task.MemoryInMB)+" MB").
+		Attribute("Created at", task.CreatedAt)
+
+	if task.State == "FAILED" {
+		n.Color = "#Pink"
+	}
+
+	return n
+}
@@ -1,7 +1,122 @@
 package services
 
+import (
+	"context"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/plantuml"
+	"github.com/nrekretep/cloudpaint/domain/diagram"
+)
+
+// Config contains the settings needed to connect to a cloud foundry
+// foundation. AuthType selects which of the credentials are used, see the
+// cloudfoundry.AuthType constants. An empty AuthType means username and
+// password.
+//
+// With UseCFConfig the session of the cf CLI is reused and all other
+// connection settings except CACertPath are ignored. CFConfigPath defaults to
+// ~/.cf/config.json. The org and space targeted by the cf CLI are used when
+// resources are looked up by name without organization.
 type Config struct {
-	Usename  string
-	Password string
-	ApiUrl   string
+	UseCFConfig  bool
+	CFConfigPath string
+	AuthType     string
+	Usename      string
+	Password     string
+	ClientID     string
+	ClientSecret string
+	Passcode     string
+	AccessToken  string
+	// RefreshToken is used with AccessToken to obtain a new access token
+	// once it is expired.
+	RefreshToken string
+	ApiUrl       string
+
+	SkipSSLValidation bool
+	CACertPath        string
+
+	MaxRetries        int
+	RequestsPerSecond float64
+	MaxConcurrency    int
+
+	// RecentTasks is the number of tasks shown per app. Zero means
+	// cloudfoundry.DefaultRecentTasks.
+	RecentTasks int
+
+	// DiagramOptions control which labels and annotations are rendered.
+	DiagramOptions diagram.Options
+
+	// Renderer turns the diagrams into their output format. Nil means
+	// plantuml.
+	Renderer diagram.Renderer
+}
+
+// cloudControllerConfig maps the service config to the config of the
+// cloud controller adapter.
+func (c *Config) cloudControllerConfig() cloudfoundry.CloudControllerConfig {
+	return cloudfoundry.CloudControllerConfig{
+		AuthType:     c.AuthType,
+		Username:     c.Usename,
+		Password:     c.Password,
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Passcode:     c.Passcode,
+		AccessToken:  c.AccessToken,
+		RefreshToken: c.RefreshToken,
+		APIURLString: c.ApiUrl,
+
+		SkipSSLValidation: c.SkipSSLValidation,
+		CACertPath:        c.CACertPath,
+
+		MaxRetries:        c.MaxRetries,
+		RequestsPerSecond: c.RequestsPerSecond,
+		MaxConcurrency:    c.MaxConcurrency,
+	}
+}
+
+// newCloudController returns a logged in CloudController for the config.
+func (c *Config) newCloudController(ctx context.Context) (*cloudfoundry.CloudController, error) {
+
+	if c.UseCFConfig {
+		cfConfig, err := cloudfoundry.LoadCFConfig(c.CFConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		cfConfig.CACertPath = c.CACertPath
+
+		return cloudfoundry.NewCloudControllerFromCFConfigContext(ctx, cfConfig)
+	}
+
+	cloudController, err := cloudfoundry.NewCloudController(c.cloudControllerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	err = cloudController.LoginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudController, nil
+}
+
+// newBuilder returns a diagram builder for the resources loaded by the
+// cloud controller.
+func (c *Config) newBuilder(cloudController *cloudfoundry.CloudController) *diagram.Builder {
+	return diagram.NewBuilder(newInventory(cloudController), c.DiagramOptions)
+}
+
+// render renders the diagram with the configured renderer.
+func (c *Config) render(d *diagram.Diagram) string {
+
+	renderer := c.Renderer
+	if renderer == nil {
+		renderer = defaultRenderer()
+	}
+
+	return renderer.Render(d)
+}
+
+// defaultRenderer returns the renderer used if none is configured.
+func defaultRenderer() diagram.Renderer {
+	return plantuml.NewPlantUML()
 }
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+
+	Convey("Given a config with an access token and a refresh token", t, func() {
+
+		config := Config{AuthType: cloudfoundry.AuthTypeAccessToken, AccessToken: "token", RefreshToken: "refreshtoken", ApiUrl: "test"}
+
+		Convey("When the config of the cloud controller is created", func() {
+
+			ccConfig := config.cloudControllerConfig()
+
+			Convey("Then the refresh token is passed to the cloud controller", func() {
+				So(ccConfig.AccessToken, ShouldEqual, "token")
+				So(ccConfig.RefreshToken, ShouldEqual, "refreshtoken")
+			})
+
+		})
+
+	})
+
+}
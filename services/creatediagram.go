@@ -2,18 +2,22 @@ package services
 
 import (
 	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
-	"github.com/nrekretep/cloudpaint/adapter/plantuml"
+	"github.com/nrekretep/cloudpaint/domain/diagram"
 )
 
 // CreateDiagramService -
 type CreateDiagramService struct {
 	CloudController *cloudfoundry.CloudController
+	// Renderer turns the diagram into its output format, it defaults to
+	// plantuml.
+	Renderer diagram.Renderer
 }
 
-// NewCreateDiagramService -
+// NewCreateDiagramService - The CloudController must have loaded the
+// inventory (GetInventory) and all apps (GetV3Apps).
 func NewCreateDiagramService(c *cloudfoundry.CloudController) *CreateDiagramService {
 
-	diagramService := &CreateDiagramService{CloudController: c}
+	diagramService := &CreateDiagramService{CloudController: c, Renderer: defaultRenderer()}
 
 	return diagramService
 }
@@ -21,7 +25,7 @@ func NewCreateDiagramService(c *cloudfoundry.CloudController) *CreateDiagramServ
 // renderTemplate -
 func (c *CreateDiagramService) RenderTemplate() string {
 
-	plantUml := plantuml.NewPlantUML(c.CloudController)
+	d := diagram.NewBuilder(newInventory(c.CloudController), diagram.Options{}).FoundationDiagram()
 
-	return plantUml.CreateDiagram()
+	return c.Renderer.Render(d)
 }
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCreateDiagram(t *testing.T) {
+
+	Convey("Given a cloud controller which loaded the inventory and all apps", t, func() {
+
+		server := testingFoundation(testingFoundationResponses())
+		defer server.Close()
+
+		config := testingConfig(server)
+		cloudController, err := config.newCloudController(context.Background())
+		So(err, ShouldEqual, nil)
+		So(cloudController.GetInventory(), ShouldEqual, nil)
+		So(cloudController.GetV3Apps(), ShouldEqual, nil)
+
+		Convey("When the foundation diagram is rendered", func() {
+
+			rawDiagram := NewCreateDiagramService(cloudController).RenderTemplate()
+
+			Convey("Then it lists all resources by name in sections of one kind", func() {
+				So(rawDiagram, ShouldEqual, `@startuml
+[cflinuxfs3] <<stack>> as cflinuxfs3
+
+[java_buildpack] <<buildpack>> as java_buildpack
+
+java_buildpack --> cflinuxfs3
+
+[my-org] <<organization>> as orgguid
+
+[my-space] <<space>> as spaceguid
+
+orgguid --> spaceguid
+
+[backend-app] <<app>> as backendappguid
+[my-app] <<app>> as appguid
+
+spaceguid --> backendappguid
+spaceguid --> appguid
+
+appguid --> java_buildpack
+
+center footer Generated with cloudpaint (https://github.com/nrekretep/cloudpaint)
+@enduml
+`)
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// testingFoundation serves a small cloud foundry foundation with a single app
+// from memory. Responses are looked up by path and query. Unknown v3 lists
+// are empty, unknown single resources are not found.
+func testingFoundation(responses map[string]string) *httptest.Server {
+	return httptest.NewServer(testingFoundationHandler(responses))
+}
+
+// testingFoundationHandler is the handler of testingFoundation. Tests wrap it
+// to fail single requests.
+func testingFoundationHandler(responses map[string]string) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`{"links": {"login": {"href": "http://` + r.Host + `"}}}`))
+			return
+		case "/oauth/token":
+			w.Write([]byte(`{"access_token": "token", "expires_in": 300}`))
+			return
+		}
+
+		key := r.URL.Path
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+
+		for _, k := range []string{key, r.URL.Path} {
+			if response, ok := responses[k]; ok {
+				w.Write([]byte(strings.Replace(response, "{{host}}", r.Host, -1)))
+				return
+			}
+		}
+
+		segments := strings.Split(r.URL.Path, "/")
+		last := segments[len(segments)-1]
+		if strings.HasPrefix(r.URL.Path, "/v3/") && !strings.HasSuffix(last, "guid") && last != "current" {
+			w.Write([]byte(`{"pagination": {"total_results": 0}, "resources": []}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Resource not found"}]}`))
+	})
+}
+
+// testingConfig returns the config to log in to the testing foundation
+// served by the server.
+func testingConfig(server *httptest.Server) Config {
+	return Config{Usename: "u", Password: "p", ApiUrl: server.URL}
+}
+
+// testingFoundationResponses returns the responses for a foundation with the
+// org my-org, the space my-space and the java app my-app.
+func testingFoundationResponses() map[string]string {
+
+	return map[string]string{
+		"/v3/apps/app-guid": testingApp,
+		"/v3/organizations": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "org-guid", "name": "my-org", "relationships": {"quota": {"data": {"guid": "org-quota-guid"}}}}
+		]}`,
+		"/v3/spaces": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "space-guid", "name": "my-space", "relationships": {"organization": {"data": {"guid": "org-guid"}}}}
+		]}`,
+		"/v3/stacks": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "stack-guid", "name": "cflinuxfs3"}
+		]}`,
+		"/v3/buildpacks": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "buildpack-guid", "name": "java_buildpack", "stack": "cflinuxfs3"}
+		]}`,
+		"/v3/apps/app-guid/processes": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "worker-process-guid", "type": "worker", "command": "java -jar worker.jar", "instances": 1, "memory_in_mb": 512, "disk_in_mb": 1024,
+				"health_check": {"type": "process", "data": {"timeout": null}}},
+			{"guid": "web-process-guid", "type": "web", "instances": 2, "memory_in_mb": 1024, "disk_in_mb": 1024,
+				"health_check": {"type": "http", "data": {"timeout": 60, "endpoint": "/health"}}}
+		]}`,
+		"/v3/processes/web-process-guid/stats": `{"resources": [
+			{"type": "web", "index": 1, "state": "CRASHED", "uptime": 0, "mem_quota": 1073741824},
+			{"type": "web", "index": 0, "state": "RUNNING", "uptime": 3723, "mem_quota": 1073741824,
+				"usage": {"time": "2019-06-08T16:41:26Z", "cpu": 0.125, "mem": 314572800, "disk": 104857600}}
+		]}`,
+		"/v3/processes/worker-process-guid/stats": `{"resources": [
+			{"type": "worker", "index": 0, "state": "RUNNING", "uptime": 60, "mem_quota": 536870912}
+		]}`,
+		"/v3/apps/app-guid/droplets/current": testingDroplet,
+		"/v3/apps/app-guid/droplets": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "newer-droplet-guid", "state": "STAGED", "stack": "cflinuxfs3", "created_at": "2019-06-09T10:00:00Z",
+				"buildpacks": [{"name": "java_buildpack", "version": "4.21"}],
+				"links": {"app": {"href": "http://{{host}}/v3/apps/app-guid"}, "package": {"href": "http://{{host}}/v3/packages/package-guid"}}},
+			` + testingDroplet + `
+		]}`,
+		"/v3/apps/app-guid/packages": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "package-guid", "type": "bits", "state": "READY", "created_at": "2019-06-08T16:30:00Z",
+				"data": {"checksum": {"type": "sha256", "value": "b1ts"}}}
+		]}`,
+		"/v3/apps/app-guid/revisions/deployed": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "revision-guid", "version": 3, "description": "New droplet deployed.", "deployable": true,
+				"droplet": {"guid": "droplet-guid"}, "created_at": "2019-06-08T16:41:26Z"}
+		]}`,
+		"/v3/apps/app-guid/sidecars": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "apm-sidecar-guid", "name": "apm-agent", "command": "./apm", "process_types": ["web", "worker"], "memory_in_mb": 64, "origin": "buildpack"},
+			{"guid": "proxy-sidecar-guid", "name": "proxy", "command": "./proxy", "process_types": ["cron"], "origin": "user"}
+		]}`,
+		"/v3/apps/app-guid/tasks": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "failed-task-guid", "sequence_id": 2, "name": "migrate", "command": "./migrate", "state": "FAILED", "memory_in_mb": 256,
+				"result": {"failure_reason": "Exited with status 1"}, "created_at": "2019-06-08T17:00:00Z"},
+			{"guid": "task-guid", "sequence_id": 1, "name": "migrate", "command": "./migrate", "state": "SUCCEEDED", "memory_in_mb": 256,
+				"result": {"failure_reason": null}, "created_at": "2019-06-08T16:50:00Z"}
+		]}`,
+		"/v3/apps/app-guid/routes": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "route-guid", "protocol": "http", "host": "my-app", "path": "/api", "url": "my-app.example.org/api",
+				"destinations": [{"guid": "destination-guid", "app": {"guid": "app-guid", "process": {"type": "web"}}, "port": 8080, "protocol": "http1"}],
+				"relationships": {"space": {"data": {"guid": "space-guid"}}, "domain": {"data": {"guid": "domain-guid"}}}},
+			{"guid": "internal-route-guid", "protocol": "http", "host": "my-app", "url": "my-app.apps.internal",
+				"relationships": {"space": {"data": {"guid": "space-guid"}}, "domain": {"data": {"guid": "internal-domain-guid"}}}}
+		]}`,
+		"/v3/routes/internal-route-guid/destinations": `{"destinations": [
+			{"guid": "internal-destination-guid", "app": {"guid": "app-guid"}, "port": 8081, "protocol": "http2"}
+		]}`,
+		"/v3/domains": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "domain-guid", "name": "example.org", "internal": false, "relationships": {"organization": {"data": null}}},
+			{"guid": "internal-domain-guid", "name": "apps.internal", "internal": true, "relationships": {"organization": {"data": null}}}
+		]}`,
+		"/v3/service_credential_bindings": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "binding-guid", "type": "app", "name": "db",
+				"relationships": {"app": {"data": {"guid": "app-guid"}}, "service_instance": {"data": {"guid": "db-guid"}}}},
+			{"guid": "ups-binding-guid", "type": "app",
+				"relationships": {"app": {"data": {"guid": "app-guid"}}, "service_instance": {"data": {"guid": "ups-guid"}}}}
+		]}`,
+		"/v3/service_instances": `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "db-guid", "name": "my-db", "type": "managed",
+				"relationships": {"space": {"data": {"guid": "space-guid"}}, "service_plan": {"data": {"guid": "plan-guid"}}}},
+			{"guid": "ups-guid", "name": "my-ups", "type": "user-provided", "credentials": {"password": "secret"},
+				"relationships": {"space": {"data": {"guid": "space-guid"}}}}
+		]}`,
+		"/v3/service_plans": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "plan-guid", "name": "small", "relationships": {"service_offering": {"data": {"guid": "offering-guid"}}}}
+		]}`,
+		"/v3/service_offerings": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "offering-guid", "name": "postgres", "relationships": {"service_broker": {"data": {"guid": "broker-guid"}}}}
+		]}`,
+		"/v3/service_brokers": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "broker-guid", "name": "db-broker"}
+		]}`,
+		"/v3/apps": `{"pagination": {"total_results": 2}, "resources": [
+			` + testingApp + `,
+			{"guid": "backend-app-guid", "name": "backend-app", "state": "STARTED",
+				"relationships": {"space": {"data": {"guid": "space-guid"}}}}
+		]}`,
+		"/networking/v1/external/policies": `{"total_policies": 2, "policies": [
+			{"source": {"id": "app-guid"}, "destination": {"id": "backend-app-guid", "protocol": "tcp", "ports": {"start": 8080, "end": 8080}}},
+			{"source": {"id": "foreign-app-guid"}, "destination": {"id": "app-guid", "protocol": "udp", "ports": {"start": 9000, "end": 9010}}}
+		]}`,
+		"/v3/organization_quotas": `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "org-quota-guid", "name": "default"}
+		]}`,
+	}
+}
+
+const testingDroplet = `{
+	"guid": "droplet-guid",
+	"state": "STAGED",
+	"stack": "cflinuxfs3",
+	"buildpacks": [{"name": "java_buildpack", "detect_output": "java", "buildpack_name": "java", "version": "4.20"}],
+	"checksum": {"type": "sha256", "value": "d40p"},
+	"created_at": "2019-06-08T16:35:00Z",
+	"links": {"app": {"href": "http://{{host}}/v3/apps/app-guid"}, "package": {"href": "http://{{host}}/v3/packages/package-guid"}}
+}`
+
+const testingApp = `{
+	"guid": "app-guid",
+	"name": "my-app",
+	"state": "STARTED",
+	"created_at": "2019-03-17T21:41:30Z",
+	"updated_at": "2019-06-08T16:41:26Z",
+	"lifecycle": {"type": "buildpack", "data": {"buildpacks": ["java_buildpack"], "stack": "cflinuxfs3"}},
+	"relationships": {"space": {"data": {"guid": "space-guid"}}},
+	"metadata": {"labels": {"team": "payments", "tier": "frontend"}, "annotations": {"contact": "payments@example.org"}}
+}`
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"github.com/nrekretep/cloudpaint/domain/diagram"
+)
+
+// inventory provides the resources loaded by a CloudController to the
+// diagram builder. It implements diagram.Inventory.
+type inventory struct {
+	cloudController *cloudfoundry.CloudController
+}
+
+// newInventory -
+func newInventory(c *cloudfoundry.CloudController) *inventory {
+	return &inventory{cloudController: c}
+}
+
+func (i *inventory) Stacks() []*v3.Stack {
+	return i.cloudController.V3Stacks()
+}
+
+func (i *inventory) Buildpacks() []*v3.Buildpack {
+	return i.cloudController.V3Buildpacks()
+}
+
+func (i *inventory) Organizations() []*v3.Organization {
+	return i.cloudController.V3Organizations()
+}
+
+func (i *inventory) Spaces() []*v3.Space {
+	return i.cloudController.V3Spaces()
+}
+
+func (i *inventory) Apps() []*v3.App {
+	return i.cloudController.V3Apps()
+}
+
+func (i *inventory) Routes() []*v3.Route {
+	return i.cloudController.V3Routes()
+}
+
+func (i *inventory) ServiceCredentialBindings() []*v3.ServiceCredentialBinding {
+	return i.cloudController.V3ServiceCredentialBindings()
+}
+
+func (i *inventory) ServiceInstances() []*v3.ServiceInstance {
+	return i.cloudController.V3ServiceInstances()
+}
+
+func (i *inventory) Organization(guid string) *v3.Organization {
+	return i.cloudController.V3Organization(guid)
+}
+
+func (i *inventory) Space(guid string) *v3.Space {
+	return i.cloudController.V3Space(guid)
+}
+
+func (i *inventory) App(guid string) *v3.App {
+	return i.cloudController.V3App(guid)
+}
+
+func (i *inventory) OrganizationQuota(guid string) *v3.OrganizationQuota {
+	return i.cloudController.V3OrganizationQuota(guid)
+}
+
+func (i *inventory) SpaceQuota(guid string) *v3.SpaceQuota {
+	return i.cloudController.V3SpaceQuota(guid)
+}
+
+func (i *inventory) Domain(guid string) *v3.Domain {
+	return i.cloudController.V3Domain(guid)
+}
+
+func (i *inventory) ServiceInstance(guid string) *v3.ServiceInstance {
+	return i.cloudController.V3ServiceInstance(guid)
+}
+
+func (i *inventory) ServicePlan(guid string) *v3.ServicePlan {
+	return i.cloudController.V3ServicePlan(guid)
+}
+
+func (i *inventory) ServiceOffering(guid string) *v3.ServiceOffering {
+	return i.cloudController.V3ServiceOffering(guid)
+}
+
+func (i *inventory) ServiceBroker(guid string) *v3.ServiceBroker {
+	return i.cloudController.V3ServiceBroker(guid)
+}
+
+func (i *inventory) ProcessesOf(appGUID string) []*v3.Process {
+	return i.cloudController.ProcessesOf(appGUID)
+}
+
+func (i *inventory) ProcessStatsOf(processGUID string) []*v3.ProcessInstanceStats {
+	return i.cloudController.ProcessStatsOf(processGUID)
+}
+
+func (i *inventory) SidecarsOf(appGUID string) []*v3.Sidecar {
+	return i.cloudController.SidecarsOf(appGUID)
+}
+
+func (i *inventory) TasksOf(appGUID string) []*v3.Task {
+	return i.cloudController.TasksOf(appGUID)
+}
+
+func (i *inventory) AppLineage(appGUID string) *v3.AppLineage {
+	return i.cloudController.V3AppLineage(appGUID)
+}
+
+func (i *inventory) SecurityGroupsOf(spaceGUID string, lifecycle string) []*v3.SecurityGroup {
+	return i.cloudController.SecurityGroupsOf(spaceGUID, lifecycle)
+}
+
+func (i *inventory) NetworkPoliciesOf(appGUID string) []diagram.NetworkPolicy {
+
+	var policies []diagram.NetworkPolicy
+	for _, policy := range i.cloudController.NetworkPoliciesOf(appGUID) {
+		policies = append(policies, policy)
+	}
+
+	return policies
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+	"strings"
+)
+
+// MultiAppDiagramService renders the apps selected by a label selector
+// and/or a name pattern across all spaces of the foundation, e.g. all apps
+// of a bounded context labeled domain=payments, together with their routes,
+// services, network policies and runtime dependencies.
+type MultiAppDiagramService struct {
+	config *Config
+}
+
+// NewMultiAppDiagramService -
+func NewMultiAppDiagramService(c *Config) (*MultiAppDiagramService, error) {
+
+	if c == nil {
+		return nil, errors.New("a non empty config must be provided to a diagram service")
+	}
+
+	diagramService := &MultiAppDiagramService{config: c}
+
+	return diagramService, nil
+}
+
+// GetRawDiagram returns the plantuml source of the diagram for the apps
+// matching the label selector and the glob pattern for their names. Either
+// may be empty, but not both.
+func (s *MultiAppDiagramService) GetRawDiagram(labelSelector string, namePattern string) (string, error) {
+	return s.GetRawDiagramContext(context.Background(), labelSelector, namePattern)
+}
+
+// GetRawDiagramContext is like GetRawDiagram but uses the given context.
+func (s *MultiAppDiagramService) GetRawDiagramContext(ctx context.Context, labelSelector string, namePattern string) (string, error) {
+
+	if labelSelector == "" && namePattern == "" {
+		return "", errors.New("a label selector or a name pattern for the apps must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := &v3.ListQuery{PerPage: v3.MaxPerPage, LabelSelector: labelSelector}
+	if namePattern != "" && !strings.ContainsAny(namePattern, `*?[\`) {
+		// without wildcards the pattern is an exact name
+		query.Filter("names", namePattern)
+	}
+
+	apps, err := cloudController.QueryV3AppsContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	if namePattern != "" {
+		apps, err = cloudfoundry.FilterV3AppsByName(apps, namePattern)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(apps) == 0 {
+		return "", errors.New("no apps found for " + selection(labelSelector, namePattern))
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	appGUIDs := make([]string, 0, len(apps))
+	for _, app := range apps {
+		appGUIDs = append(appGUIDs, app.GUID)
+	}
+
+	spaces, orgs, err := cloudController.GetV3AppSpacesContext(ctx, apps)
+	if err != nil {
+		return "", err
+	}
+
+	policyWarning, err := loadAppDependencies(ctx, cloudController, apps)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = cloudController.QueryV3RoutesContext(ctx, (&v3.ListQuery{PerPage: v3.MaxPerPage}).Filter("app_guids", appGUIDs...))
+	if err != nil {
+		return "", err
+	}
+
+	d := s.config.newBuilder(cloudController).MultiAppDiagram("Multi App Diagram - "+selection(labelSelector, namePattern), orgs, spaces, apps)
+	addNetworkPolicyWarning(d, policyWarning)
+
+	return s.config.render(d), nil
+}
+
+// selection describes the label selector and the name pattern.
+func selection(labelSelector string, namePattern string) string {
+
+	switch {
+	case labelSelector != "" && namePattern != "":
+		return labelSelector + " / " + namePattern
+	case labelSelector != "":
+		return labelSelector
+	}
+
+	return namePattern
+}
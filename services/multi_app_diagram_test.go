@@ -0,0 +1,114 @@
+package services
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestMultiAppDiagram(t *testing.T) {
+
+	Convey("Given the config for the diagram does not exist", t, func() {
+
+		Convey("When the MultiAppDiagram Service is created", func() {
+
+			Convey("Then an error messages indicates the missing config", func() {
+				diagramService, err := NewMultiAppDiagramService(nil)
+
+				So(diagramService, ShouldEqual, nil)
+				So(err.Error(), ShouldEqual, "a non empty config must be provided to a diagram service")
+			})
+
+		})
+
+	})
+
+	Convey("Given apps of a bounded context in two spaces sharing a service", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/apps?label_selector=domain%3Dpayments&per_page=5000"] = `{"pagination": {"total_results": 2}, "resources": [
+			` + testingApp + `,
+			{"guid": "worker-app-guid", "name": "payment-worker", "state": "STARTED",
+				"lifecycle": {"type": "buildpack", "data": {"buildpacks": ["java_buildpack"], "stack": "cflinuxfs3"}},
+				"relationships": {"space": {"data": {"guid": "jobs-space-guid"}}}}
+		]}`
+		responses["/v3/routes"] = responses["/v3/apps/app-guid/routes"]
+		responses["/v3/spaces"] = `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "space-guid", "name": "my-space", "relationships": {"organization": {"data": {"guid": "org-guid"}}}},
+			{"guid": "jobs-space-guid", "name": "jobs", "relationships": {"organization": {"data": {"guid": "org-guid"}}}}
+		]}`
+		responses["/v3/service_credential_bindings"] = `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "binding-guid", "type": "app", "name": "db",
+				"relationships": {"app": {"data": {"guid": "app-guid"}}, "service_instance": {"data": {"guid": "db-guid"}}}},
+			{"guid": "worker-binding-guid", "type": "app", "name": "db",
+				"relationships": {"app": {"data": {"guid": "worker-app-guid"}}, "service_instance": {"data": {"guid": "db-guid"}}}}
+		]}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		diagramService, _ := NewMultiAppDiagramService(&config)
+
+		Convey("When the MultiAppDiagram is rendered for a label selector", func() {
+
+			diagram, err := diagramService.GetRawDiagram("domain=payments", "")
+
+			Convey("Then the apps are grouped by their spaces", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Multi App Diagram - domain=payments\n")
+				So(diagram, ShouldContainSubstring, "package \"my-org\" as orgguid <<organization>> {\n")
+				So(diagram, ShouldContainSubstring, "package \"jobs\" as jobsspaceguid <<space>> {\ncomponent workerappguid <<app>> [\n**payment-worker**\n")
+				So(diagram, ShouldContainSubstring, "package \"my-space\" as spaceguid <<space>> {\ncomponent appguid <<app>> [\n**my-app**\n")
+			})
+
+			Convey("Then shared services and runtime dependencies are written once", func() {
+				So(strings.Count(diagram, "component dbguid "), ShouldEqual, 1)
+				So(diagram, ShouldContainSubstring, "appguid --> dbguid : db\n")
+				So(diagram, ShouldContainSubstring, "workerappguid --> dbguid : db\n")
+				So(strings.Count(diagram, "[**java_buildpack**]"), ShouldEqual, 1)
+			})
+
+			Convey("Then routes and network policies of the apps are shown", func() {
+				So(diagram, ShouldContainSubstring, "component routeguid ")
+				So(diagram, ShouldContainSubstring, "appguid ..> backendappguid : tcp 8080\n")
+			})
+
+		})
+
+		Convey("When the MultiAppDiagram is rendered for a name pattern", func() {
+
+			diagram, err := diagramService.GetRawDiagram("", "backend-*")
+
+			Convey("Then only the apps with matching names are shown", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Multi App Diagram - backend-*\n")
+				So(diagram, ShouldContainSubstring, "package \"my-space\" as spaceguid <<space>> {\ncomponent backendappguid <<app>> [\n**backend-app**\nState: STARTED\nCreated at: \nUpdated at: \n]\n}\n")
+			})
+
+		})
+
+		Convey("When no app matches the name pattern", func() {
+
+			_, err := diagramService.GetRawDiagram("", "billing-*")
+
+			Convey("Then an error messages indicates the selection", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "no apps found for billing-*")
+			})
+
+		})
+
+		Convey("When neither a label selector nor a name pattern is given", func() {
+
+			_, err := diagramService.GetRawDiagram("", "")
+
+			Convey("Then an error messages indicates the missing selection", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "a label selector or a name pattern for the apps must be provided")
+			})
+
+		})
+
+	})
+
+}
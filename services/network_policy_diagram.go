@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"github.com/nrekretep/cloudpaint/domain/diagram"
+	"sort"
+)
+
+// NetworkPolicyDiagramService renders the container-to-container network
+// policies of all apps of a space or an organization.
+type NetworkPolicyDiagramService struct {
+	config *Config
+}
+
+// NewNetworkPolicyDiagramService -
+func NewNetworkPolicyDiagramService(c *Config) (*NetworkPolicyDiagramService, error) {
+
+	if c == nil {
+		return nil, errors.New("a non empty config must be provided to a diagram service")
+	}
+
+	diagramService := &NetworkPolicyDiagramService{config: c}
+
+	return diagramService, nil
+}
+
+// GetRawSpaceDiagram returns the plantuml source of the policy diagram for
+// the apps of the space.
+func (s *NetworkPolicyDiagramService) GetRawSpaceDiagram(spaceID string) (string, error) {
+	return s.GetRawSpaceDiagramContext(context.Background(), spaceID)
+}
+
+// GetRawSpaceDiagramContext is like GetRawSpaceDiagram but uses the given
+// context.
+func (s *NetworkPolicyDiagramService) GetRawSpaceDiagramContext(ctx context.Context, spaceID string) (string, error) {
+
+	if spaceID == "" {
+		return "", errors.New("a valid id for the space must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	spaces, err := cloudController.QueryV3SpacesContext(ctx, (&v3.ListQuery{}).Filter("guids", spaceID))
+	if err != nil {
+		return "", err
+	}
+	if len(spaces) == 0 {
+		return "", errors.New("space with id " + spaceID + " not found")
+	}
+
+	return s.render(ctx, cloudController, "Network Policy Diagram - space "+spaces[0].Name, "space_guids", spaceID)
+}
+
+// GetRawOrgDiagram returns the plantuml source of the policy diagram for the
+// apps of all spaces of the organization.
+func (s *NetworkPolicyDiagramService) GetRawOrgDiagram(orgID string) (string, error) {
+	return s.GetRawOrgDiagramContext(context.Background(), orgID)
+}
+
+// GetRawOrgDiagramContext is like GetRawOrgDiagram but uses the given
+// context.
+func (s *NetworkPolicyDiagramService) GetRawOrgDiagramContext(ctx context.Context, orgID string) (string, error) {
+
+	if orgID == "" {
+		return "", errors.New("a valid id for the organization must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	orgs, err := cloudController.QueryV3OrganizationsContext(ctx, (&v3.ListQuery{}).Filter("guids", orgID))
+	if err != nil {
+		return "", err
+	}
+	if len(orgs) == 0 {
+		return "", errors.New("organization with id " + orgID + " not found")
+	}
+
+	return s.render(ctx, cloudController, "Network Policy Diagram - organization "+orgs[0].Name, "organization_guids", orgID)
+}
+
+// render loads the apps matching the filter and their network policies and
+// renders the diagram.
+func (s *NetworkPolicyDiagramService) render(ctx context.Context, cloudController *cloudfoundry.CloudController, title string, filter string, guid string) (string, error) {
+
+	apps, err := cloudController.QueryV3AppsContext(ctx, (&v3.ListQuery{PerPage: v3.MaxPerPage}).Filter(filter, guid))
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	appGUIDs := make([]string, 0, len(apps))
+	for _, app := range apps {
+		appGUIDs = append(appGUIDs, app.GUID)
+	}
+
+	if len(appGUIDs) > 0 {
+		_, err = cloudController.GetAppNetworkPoliciesContext(ctx, appGUIDs...)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	d := s.config.newBuilder(cloudController).NetworkPolicyDiagram(title, apps)
+
+	return s.config.render(d), nil
+}
+
+// loadNetworkPolicies loads the network policies of the apps for diagrams
+// which show policies beside other dependencies. Users without the
+// network.admin or network.write scope are denied by the policy server and
+// foundations without policy server answer with not found. Both are no
+// reason to fail the whole diagram, the returned warning explains the
+// missing policies instead.
+func loadNetworkPolicies(ctx context.Context, cloudController *cloudfoundry.CloudController, appGUIDs ...string) (string, error) {
+
+	_, err := cloudController.GetAppNetworkPoliciesContext(ctx, appGUIDs...)
+	switch {
+	case cloudfoundry.IsForbidden(err):
+		return "access denied by the policy server, network.admin or network.write scope required", nil
+	case cloudfoundry.IsNotFound(err):
+		return "no policy server found", nil
+	}
+
+	return "", err
+}
+
+// addNetworkPolicyWarning adds the warning of loadNetworkPolicies to the
+// diagram.
+func addNetworkPolicyWarning(d *diagram.Diagram, warning string) {
+
+	if warning == "" {
+		return
+	}
+
+	d.AddWarning("network_policies_warning", "Network policies not shown", warning)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestNetworkPolicyDiagram(t *testing.T) {
+
+	Convey("Given the config for the diagram does not exist", t, func() {
+
+		Convey("When the NetworkPolicyDiagram Service is created", func() {
+
+			Convey("Then an error messages indicates the missing config", func() {
+				diagramService, err := NewNetworkPolicyDiagramService(nil)
+
+				So(diagramService, ShouldEqual, nil)
+				So(err.Error(), ShouldEqual, "a non empty config must be provided to a diagram service")
+			})
+
+		})
+
+	})
+
+	Convey("Given a space with apps and network policies", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/spaces?guids=unknown-guid"] = `{"pagination": {"total_results": 0}, "resources": []}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		diagramService, _ := NewNetworkPolicyDiagramService(&config)
+
+		Convey("When the NetworkPolicyDiagram of the space is rendered", func() {
+
+			diagram, err := diagramService.GetRawSpaceDiagram("space-guid")
+
+			Convey("Then the diagram shows the apps of the space and their policies", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Network Policy Diagram - space my-space\n")
+				So(diagram, ShouldContainSubstring, "component appguid <<app>> [\n**my-app**\n")
+				So(diagram, ShouldContainSubstring, "component backendappguid <<app>> [\n**backend-app**\n")
+				So(diagram, ShouldContainSubstring, "appguid ..> backendappguid : tcp 8080\n")
+				So(diagram, ShouldContainSubstring, "foreignappguid ..> appguid : udp 9000-9010\n")
+				So(strings.Count(diagram, "appguid ..> backendappguid"), ShouldEqual, 1)
+			})
+
+		})
+
+		Convey("When the NetworkPolicyDiagram of an unknown space is rendered", func() {
+
+			_, err := diagramService.GetRawSpaceDiagram("unknown-guid")
+
+			Convey("Then an error messages indicates the wrong space ID", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "space with id unknown-guid not found")
+			})
+
+		})
+
+	})
+
+}
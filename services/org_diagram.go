@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// OrgDiagramService renders the overview of an organization with its
+// spaces, apps and quotas.
+type OrgDiagramService struct {
+	config *Config
+}
+
+// NewOrgDiagramService -
+func NewOrgDiagramService(c *Config) (*OrgDiagramService, error) {
+
+	if c == nil {
+		return nil, errors.New("a non empty config must be provided to a diagram service")
+	}
+
+	diagramService := &OrgDiagramService{config: c}
+
+	return diagramService, nil
+}
+
+// GetRawDiagram returns the plantuml source of the diagram for the
+// organization.
+func (s *OrgDiagramService) GetRawDiagram(orgID string) (string, error) {
+	return s.GetRawDiagramContext(context.Background(), orgID)
+}
+
+// GetRawDiagramContext is like GetRawDiagram but uses the given context.
+func (s *OrgDiagramService) GetRawDiagramContext(ctx context.Context, orgID string) (string, error) {
+
+	if orgID == "" {
+		return "", errors.New("a valid id for the organization must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	orgs, err := cloudController.QueryV3OrganizationsContext(ctx, (&v3.ListQuery{}).Filter("guids", orgID))
+	if err != nil {
+		return "", err
+	}
+	if len(orgs) == 0 {
+		return "", errors.New("organization with id " + orgID + " not found")
+	}
+
+	return s.render(ctx, cloudController, orgs[0])
+}
+
+// GetRawDiagramByName returns the plantuml source of the diagram for the
+// organization with the name.
+func (s *OrgDiagramService) GetRawDiagramByName(orgName string) (string, error) {
+	return s.GetRawDiagramByNameContext(context.Background(), orgName)
+}
+
+// GetRawDiagramByNameContext is like GetRawDiagramByName but uses the given
+// context.
+func (s *OrgDiagramService) GetRawDiagramByNameContext(ctx context.Context, orgName string) (string, error) {
+
+	if orgName == "" {
+		return "", errors.New("the name of the organization must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	org, err := cloudController.ResolveOrganizationContext(ctx, orgName)
+	if err != nil {
+		return "", err
+	}
+
+	return s.render(ctx, cloudController, org)
+}
+
+// render loads spaces, apps and quotas of the organization and renders the
+// diagram.
+func (s *OrgDiagramService) render(ctx context.Context, cloudController *cloudfoundry.CloudController, org *v3.Organization) (string, error) {
+
+	spaces, err := cloudController.QueryV3SpacesContext(ctx, (&v3.ListQuery{PerPage: v3.MaxPerPage}).Filter("organization_guids", org.GUID))
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(spaces, func(i, j int) bool { return spaces[i].Name < spaces[j].Name })
+
+	apps, err := cloudController.QueryV3AppsContext(ctx, (&v3.ListQuery{PerPage: v3.MaxPerPage}).Filter("organization_guids", org.GUID))
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	err = cloudController.GetV3OrganizationQuotasContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = cloudController.GetV3SpaceQuotasContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	d := s.config.newBuilder(cloudController).OrgDiagram(org, spaces, apps)
+
+	return s.config.render(d), nil
+}
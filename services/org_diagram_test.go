@@ -0,0 +1,70 @@
+package services
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestOrgDiagram(t *testing.T) {
+
+	Convey("Given the config for the diagram does not exist", t, func() {
+
+		Convey("When the OrgDiagram Service is created", func() {
+
+			Convey("Then an error messages indicates the missing config", func() {
+				diagramService, err := NewOrgDiagramService(nil)
+
+				So(diagramService, ShouldEqual, nil)
+				So(err.Error(), ShouldEqual, "a non empty config must be provided to a diagram service")
+			})
+
+		})
+
+	})
+
+	Convey("Given an organization with quotas, spaces and apps", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/organization_quotas"] = `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "org-quota-guid", "name": "default",
+				"apps": {"total_memory_in_mb": 10240, "per_process_memory_in_mb": null, "total_instances": 50},
+				"services": {"paid_services_allowed": true, "total_service_instances": 10},
+				"routes": {"total_routes": null}, "domains": {"total_domains": 2}}
+		]}`
+		responses["/v3/space_quotas"] = `{"pagination": {"total_results": 1}, "resources": [
+			{"guid": "space-quota-guid", "name": "small", "apps": {"total_memory_in_mb": 2048}}
+		]}`
+		responses["/v3/spaces"] = `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "space-guid", "name": "my-space", "relationships": {"organization": {"data": {"guid": "org-guid"}}, "quota": {"data": {"guid": "space-quota-guid"}}}},
+			{"guid": "empty-space-guid", "name": "empty-space", "relationships": {"organization": {"data": {"guid": "org-guid"}}}}
+		]}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		diagramService, _ := NewOrgDiagramService(&config)
+
+		Convey("When the OrgDiagram is rendered", func() {
+
+			diagram, err := diagramService.GetRawDiagramByName("my-org")
+
+			Convey("Then the spaces are nested in the org and the apps in their spaces", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Organization Diagram - my-org\n")
+				So(diagram, ShouldContainSubstring, "package \"my-org\" as orgguid <<organization>> {\n"+
+					"rectangle orgquotaguid <<organization quota>> [\n**default**\nTotal memory: 10240 MB\nMemory per process: unlimited\nInstances: 50\n"+
+					"Service instances: 10\nPaid services: true\nRoutes: unlimited\nDomains: 2\n]\n"+
+					"package \"empty-space\" as emptyspaceguid <<space>> {\n}\n"+
+					"package \"my-space\" as spaceguid <<space>> {\n"+
+					"rectangle spacequotaguid_spaceguid <<space quota>> [\n**small**\nTotal memory: 2048 MB\nMemory per process: unlimited\nInstances: unlimited\n]\n"+
+					"component backendappguid <<app>> [\n**backend-app**\nState: STARTED\nCreated at: \nUpdated at: \n]\n"+
+					"component appguid <<app>> [\n**my-app**\n")
+				So(diagram, ShouldContainSubstring, "Updated at: 2019-06-08T16:41:26Z\n]\n}\n}\n")
+				So(diagram, ShouldNotContainSubstring, "-->")
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+	"strings"
+)
+
+// SecurityGroupDiagramService shows which spaces, and therefore which apps,
+// can reach which destinations according to the running and staging
+// security groups.
+type SecurityGroupDiagramService struct {
+	config *Config
+}
+
+// NewSecurityGroupDiagramService -
+func NewSecurityGroupDiagramService(c *Config) (*SecurityGroupDiagramService, error) {
+
+	if c == nil {
+		return nil, errors.New("a non empty config must be provided to a diagram service")
+	}
+
+	diagramService := &SecurityGroupDiagramService{config: c}
+
+	return diagramService, nil
+}
+
+// GetRawDiagram returns the plantuml source of the security group diagram
+// for the spaces. Without space ids all spaces of the foundation are shown.
+func (s *SecurityGroupDiagramService) GetRawDiagram(spaceIDs ...string) (string, error) {
+	return s.GetRawDiagramContext(context.Background(), spaceIDs...)
+}
+
+// GetRawDiagramContext is like GetRawDiagram but uses the given context.
+func (s *SecurityGroupDiagramService) GetRawDiagramContext(ctx context.Context, spaceIDs ...string) (string, error) {
+
+	cloudController, spaces, apps, err := s.load(ctx, spaceIDs)
+	if err != nil {
+		return "", err
+	}
+
+	d := s.config.newBuilder(cloudController).SecurityGroupDiagram("Security Group Diagram", spaces, apps)
+
+	return s.config.render(d), nil
+}
+
+// GetReport returns the security group rules which apply to the spaces.
+// Without space ids all spaces of the foundation are reported.
+func (s *SecurityGroupDiagramService) GetReport(spaceIDs ...string) (*SecurityGroupReport, error) {
+	return s.GetReportContext(context.Background(), spaceIDs...)
+}
+
+// GetReportContext is like GetReport but uses the given context.
+func (s *SecurityGroupDiagramService) GetReportContext(ctx context.Context, spaceIDs ...string) (*SecurityGroupReport, error) {
+
+	cloudController, spaces, apps, err := s.load(ctx, spaceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SecurityGroupReport{}
+	for _, space := range spaces {
+
+		orgName := ""
+		if org := cloudController.V3Organization(space.OrganizationGUID()); org != nil {
+			orgName = org.Name
+		}
+
+		var appNames []string
+		for _, app := range apps {
+			if app.SpaceGUID() == space.GUID {
+				appNames = append(appNames, app.Name)
+			}
+		}
+
+		for _, lifecycle := range []string{v3.SecurityGroupRunning, v3.SecurityGroupStaging} {
+			for _, group := range cloudController.SecurityGroupsOf(space.GUID, lifecycle) {
+				for _, rule := range group.Rules {
+					report.Entries = append(report.Entries, &SecurityGroupReportEntry{
+						Organization:  orgName,
+						Space:         space.Name,
+						Apps:          appNames,
+						SecurityGroup: group.Name,
+						Lifecycle:     lifecycle,
+						Rule:          rule.String(),
+						Broad:         rule.Broad(),
+					})
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// load loads the inventory, the security groups and the apps of the spaces.
+// The spaces and apps are returned sorted by name.
+func (s *SecurityGroupDiagramService) load(ctx context.Context, spaceIDs []string) (*cloudfoundry.CloudController, []*v3.Space, []*v3.App, error) {
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = cloudController.GetInventoryContext(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var spaces []*v3.Space
+	if len(spaceIDs) == 0 {
+		spaces = cloudController.V3Spaces()
+	}
+	for _, spaceID := range spaceIDs {
+		space := cloudController.V3Space(spaceID)
+		if space == nil {
+			return nil, nil, nil, errors.New("space with id " + spaceID + " not found")
+		}
+		spaces = append(spaces, space)
+	}
+	sort.Slice(spaces, func(i, j int) bool { return spaces[i].Name < spaces[j].Name })
+
+	err = cloudController.GetV3SecurityGroupsContext(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	query := &v3.ListQuery{PerPage: v3.MaxPerPage}
+	if len(spaceIDs) > 0 {
+		query.Filter("space_guids", spaceIDs...)
+	}
+	apps, err := cloudController.QueryV3AppsContext(ctx, query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	return cloudController, spaces, apps, nil
+}
+
+// SecurityGroupReport lists every security group rule per space it applies
+// to.
+type SecurityGroupReport struct {
+	Entries []*SecurityGroupReportEntry
+}
+
+// SecurityGroupReportEntry - A rule of a security group applying to the apps
+// of a space in the running or staging lifecycle.
+type SecurityGroupReportEntry struct {
+	Organization  string
+	Space         string
+	Apps          []string
+	SecurityGroup string
+	Lifecycle     string
+	Rule          string
+	// Broad is set for rules allowing all destinations on all ports.
+	Broad bool
+}
+
+// BroadEntries returns the entries with overly broad rules.
+func (r *SecurityGroupReport) BroadEntries() []*SecurityGroupReportEntry {
+
+	var entries []*SecurityGroupReportEntry
+	for _, e := range r.Entries {
+		if e.Broad {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// String returns the report as tab separated lines. Overly broad rules are
+// marked with an exclamation mark.
+func (r *SecurityGroupReport) String() string {
+
+	var sb strings.Builder
+	sb.WriteString("org\tspace\tapps\tsecurity group\tlifecycle\trule\tbroad\n")
+	for _, e := range r.Entries {
+		broad := ""
+		if e.Broad {
+			broad = "!"
+		}
+		fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Organization, e.Space, strings.Join(e.Apps, ","), e.SecurityGroup, e.Lifecycle, e.Rule, broad)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSecurityGroupDiagram(t *testing.T) {
+
+	Convey("Given a space with a bound and a global overly broad security group", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/security_groups"] = `{"pagination": {"total_results": 2}, "resources": [
+			{"guid": "backend-sg-guid", "name": "backend", "globally_enabled": {"running": false, "staging": false},
+				"rules": [{"protocol": "tcp", "destination": "10.0.0.0/8", "ports": "443"}],
+				"relationships": {"running_spaces": {"data": [{"guid": "space-guid"}]}, "staging_spaces": {"data": [{"guid": "space-guid"}]}}},
+			{"guid": "public-sg-guid", "name": "public_networks", "globally_enabled": {"running": true, "staging": false},
+				"rules": [{"protocol": "all", "destination": "0.0.0.0/0"}],
+				"relationships": {"running_spaces": {"data": []}, "staging_spaces": {"data": []}}}
+		]}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		diagramService, _ := NewSecurityGroupDiagramService(&config)
+
+		Convey("When the SecurityGroupDiagram of the space is rendered", func() {
+
+			diagram, err := diagramService.GetRawDiagram("space-guid")
+
+			Convey("Then the diagram shows the space, its apps and the security groups", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "spaceguid --> appguid\n")
+				So(diagram, ShouldContainSubstring, "component backendsgguid <<security group>> [\n**backend**\ntcp 10.0.0.0/8:443\n]\n")
+				So(diagram, ShouldContainSubstring, "spaceguid --> backendsgguid : running, staging\n")
+				So(diagram, ShouldContainSubstring, "spaceguid --> publicsgguid : running\n")
+			})
+
+			Convey("Then the overly broad security group is flagged", func() {
+				So(diagram, ShouldContainSubstring, "component publicsgguid <<security group>> <<overly broad>> #Pink [\n**public_networks**\nGlobal: running\n<color:red>all 0.0.0.0/0 (overly broad)</color>\n]\n")
+			})
+
+		})
+
+		Convey("When the SecurityGroupReport of the space is created", func() {
+
+			report, err := diagramService.GetReport("space-guid")
+
+			Convey("Then every rule applying to the space is reported", func() {
+				So(err, ShouldEqual, nil)
+				So(len(report.Entries), ShouldEqual, 3)
+				So(len(report.BroadEntries()), ShouldEqual, 1)
+				So(*report.BroadEntries()[0], ShouldResemble, SecurityGroupReportEntry{Organization: "my-org", Space: "my-space", Apps: []string{"backend-app", "my-app"},
+					SecurityGroup: "public_networks", Lifecycle: "running", Rule: "all 0.0.0.0/0", Broad: true})
+				So(report.String(), ShouldContainSubstring, "my-org\tmy-space\tbackend-app,my-app\tpublic_networks\trunning\tall 0.0.0.0/0\t!\n")
+			})
+
+		})
+
+		Convey("When the SecurityGroupDiagram of an unknown space is rendered", func() {
+
+			_, err := diagramService.GetRawDiagram("unknown-guid")
+
+			Convey("Then an error messages indicates the wrong space ID", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "space with id unknown-guid not found")
+			})
+
+		})
+
+	})
+
+}
@@ -1,10 +1,11 @@
 package services
 
 import (
+	"context"
 	"errors"
 	//"fmt"
 	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
-	"github.com/nrekretep/cloudpaint/adapter/plantuml"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
 )
 
 // SingleAppDiagramService -
@@ -24,53 +25,89 @@ func NewSingleAppDiagramService(c *Config) (*SingleAppDiagramService, error) {
 	return diagramService, nil
 }
 
-// renderTemplate -
+// GetRawDiagram returns the plantuml source of the diagram for the app.
 func (s *SingleAppDiagramService) GetRawDiagram(appID string) (string, error) {
+	return s.GetRawDiagramContext(context.Background(), appID)
+}
+
+// GetRawDiagramContext is like GetRawDiagram but cancels all requests to the
+// cc API when the context is done.
+func (s *SingleAppDiagramService) GetRawDiagramContext(ctx context.Context, appID string) (string, error) {
 
 	if appID == "" {
 		return "", errors.New("a valid id for the app must be provided")
 	}
 
-	cloudControllerConfig := cloudfoundry.CloudControllerConfig{Username: s.config.Usename, Password: s.config.Password, APIURLString: s.config.ApiUrl}
-	cloudController, err := cloudfoundry.NewCloudController(cloudControllerConfig)
-
+	cloudController, err := s.config.newCloudController(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	err = cloudController.Login()
+	app, err := cloudController.GetV3AppContext(ctx, appID)
+	if cloudfoundry.IsNotFound(err) {
+		return "", errors.New("app with id " + appID + " not found")
+	}
 	if err != nil {
 		return "", err
 	}
 
-	app, err := cloudController.GetV3App(appID)
+	return s.render(ctx, cloudController, app)
+}
+
+// GetRawDiagramByNames returns the plantuml source of the diagram for the app
+// with the name in the space with the name of the organization with the name.
+// Empty organization or space names widen the search, an error is returned
+// when the app name is ambiguous.
+func (s *SingleAppDiagramService) GetRawDiagramByNames(orgName string, spaceName string, appName string) (string, error) {
+	return s.GetRawDiagramByNamesContext(context.Background(), orgName, spaceName, appName)
+}
+
+// GetRawDiagramByNamesContext is like GetRawDiagramByNames but uses the
+// given context.
+func (s *SingleAppDiagramService) GetRawDiagramByNamesContext(ctx context.Context, orgName string, spaceName string, appName string) (string, error) {
+
+	if appName == "" {
+		return "", errors.New("the name of the app must be provided")
+	}
 
+	cloudController, err := s.config.newCloudController(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	cloudController.GetOrganizations()
+	app, err := cloudController.ResolveAppContext(ctx, orgName, spaceName, appName)
 	if err != nil {
 		return "", err
 	}
 
-	cloudController.GetSpaces()
+	return s.render(ctx, cloudController, app)
+}
+
+// render loads the resources of the app and renders the diagram.
+func (s *SingleAppDiagramService) render(ctx context.Context, cloudController *cloudfoundry.CloudController, app *v3.App) (string, error) {
+
+	appID := app.GUID
+
+	err := cloudController.GetInventoryContext(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	cloudController.GetStacks()
+	err = cloudController.GetV3AppResourcesContext(ctx, appID, s.config.RecentTasks)
 	if err != nil {
 		return "", err
 	}
 
-	cloudController.GetBuildpacks()
+	policyWarning, err := loadNetworkPolicies(ctx, cloudController, appID)
 	if err != nil {
 		return "", err
 	}
 
-	plantUml := plantuml.NewPlantUML(cloudController)
-
-	return plantUml.CreateSingleAppDiagram(app), nil
+	d, err := s.config.newBuilder(cloudController).SingleAppDiagram(app)
+	if err != nil {
+		return "", err
+	}
+	addNetworkPolicyWarning(d, policyWarning)
 
+	return s.config.render(d), nil
 }
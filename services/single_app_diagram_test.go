@@ -1,7 +1,11 @@
 package services
 
 import (
+	"github.com/nrekretep/cloudpaint/domain/diagram"
 	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -63,10 +67,351 @@ func TestSingleAppDiagram(t *testing.T) {
 
 		Convey("When the SingleAppDiagram is rendered", func() {
 
-			Convey("Then an error messages indicates the wrong app ID", nil)
+			Convey("Then an error messages indicates the wrong app ID", func() {
+				h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/v2/info":
+						w.Write([]byte(`{"authorization_endpoint": "http://` + r.Host + `"}`))
+					case "/oauth/token":
+						w.Write([]byte(`{"access_token": "token", "expires_in": 300}`))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte(`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "App not found"}]}`))
+					}
+				})
+				server := httptest.NewServer(h)
+				defer server.Close()
+
+				config := testingConfig(server)
+				singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+				_, err := singleAppDiagramService.GetRawDiagram("unknown-guid")
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "app with id unknown-guid not found")
+			})
+
+		})
+
+	})
+
+	Convey("Given app with the given ID exists", t, func() {
+
+		server := testingFoundation(testingFoundationResponses())
+		defer server.Close()
+
+		config := testingConfig(server)
+		singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			diagram, err := singleAppDiagramService.GetRawDiagram("app-guid")
+			So(err, ShouldEqual, nil)
+
+			Convey("Then the diagram shows the app with its space, org, buildpack and stack", func() {
+				So(diagram, ShouldStartWith, "@startuml\n")
+				So(diagram, ShouldContainSubstring, "title Single App Diagram - my-app\n")
+				So(diagram, ShouldContainSubstring, "[**my-space**] <<space>> as spaceguid\n")
+				So(diagram, ShouldContainSubstring, "[**my-org**] <<organization>> as orgguid\n")
+				So(diagram, ShouldContainSubstring, "orgguid --> spaceguid\n")
+				So(diagram, ShouldContainSubstring, "appguid --> java_buildpack\n")
+				So(diagram, ShouldContainSubstring, "appguid --> cflinuxfs3\n")
+				So(diagram, ShouldEndWith, "@enduml\n")
+			})
+
+			Convey("Then the processes are nested inside the app", func() {
+				So(diagram, ShouldContainSubstring, "\" as appguid <<app>> {\n"+
+					"component webprocessguid <<process>> [\n**web**\nInstances: 1/2 running\nMemory: 1024 MB\nDisk: 1024 MB\nHealth check: http /health\n]\n"+
+					"component workerprocessguid <<process>> [\n**worker**\nInstances: 1/1 running\nMemory: 512 MB\nDisk: 1024 MB\nHealth check: process\nCommand: java -jar worker.jar\n]\n"+
+					"}\n")
+				So(diagram, ShouldNotContainSubstring, "<<instance>>")
+			})
+
+			Convey("Then the lineage shows package, droplets and revision", func() {
+				So(diagram, ShouldContainSubstring, "component packageguid <<package>> [\n**bits package**\nState: READY\nChecksum: sha256:b1ts\nCreated at: 2019-06-08T16:30:00Z\n]\n")
+				So(diagram, ShouldContainSubstring, "component dropletguid <<droplet>> <<current>> [\n**droplet**\nState: STAGED\nStack: cflinuxfs3\nBuildpack: java_buildpack 4.20\nChecksum: sha256:d40p\nStaged at: 2019-06-08T16:35:00Z\nNewer staged droplets: 1\n]\n")
+				So(diagram, ShouldContainSubstring, "component newerdropletguid <<droplet>> <<newer>> #LightYellow [\n")
+				So(diagram, ShouldContainSubstring, "component revisionguid <<revision>> [\n**revision 3**\nDescription: New droplet deployed.\nCreated at: 2019-06-08T16:41:26Z\n]\n")
+				So(diagram, ShouldContainSubstring, "packageguid --> dropletguid\n")
+				So(diagram, ShouldContainSubstring, "packageguid --> newerdropletguid\n")
+				So(diagram, ShouldContainSubstring, "dropletguid --> revisionguid\n")
+				So(diagram, ShouldContainSubstring, "revisionguid --> appguid : running\n")
+				So(strings.Count(diagram, "component packageguid "), ShouldEqual, 1)
+			})
+
+			Convey("Then the sidecars and the recent tasks are shown", func() {
+				So(diagram, ShouldContainSubstring, "component apmsidecarguid <<sidecar>> [\n**apm-agent**\nCommand: ./apm\nProcess types: web, worker\nMemory: 64 MB\nOrigin: buildpack\n]\n")
+				So(diagram, ShouldContainSubstring, "webprocessguid --> apmsidecarguid : sidecar\n")
+				So(diagram, ShouldContainSubstring, "workerprocessguid --> apmsidecarguid : sidecar\n")
+				So(diagram, ShouldContainSubstring, "appguid --> proxysidecarguid : sidecar\n")
+				So(diagram, ShouldContainSubstring, "component failedtaskguid <<task>> #Pink [\n**migrate**\nState: FAILED\nFailure reason: Exited with status 1\nCommand: ./migrate\nMemory: 256 MB\nCreated at: 2019-06-08T17:00:00Z\n]\n")
+				So(diagram, ShouldContainSubstring, "appguid --> taskguid : task\n")
+				So(strings.Index(diagram, "component failedtaskguid"), ShouldBeLessThan, strings.Index(diagram, "component taskguid"))
+			})
+
+			Convey("Then the routes are shown with their domains", func() {
+				So(diagram, ShouldContainSubstring, "[**example.org**] <<shared domain>> as domainguid\n")
+				So(diagram, ShouldContainSubstring, "component routeguid <<route>> [\n**my-app.example.org/api**\nProtocol: http\nPath: /api\n]\n")
+				So(diagram, ShouldContainSubstring, "domainguid --> routeguid\n")
+				So(diagram, ShouldContainSubstring, "routeguid --> appguid : 8080 http1 (web)\n")
+				So(diagram, ShouldContainSubstring, "[**apps.internal**] <<internal domain>> as internaldomainguid\n")
+				So(diagram, ShouldContainSubstring, "internalrouteguid --> appguid : 8081 http2 (web)\n")
+			})
+
+			Convey("Then the bound service instances are shown without credentials", func() {
+				So(diagram, ShouldContainSubstring, "component dbguid <<managed service>> [\n**my-db**\nOffering: postgres\nPlan: small\nBroker: db-broker\n]\n")
+				So(diagram, ShouldContainSubstring, "appguid --> dbguid : db\n")
+				So(diagram, ShouldContainSubstring, "component upsguid <<user-provided service>> [\n**my-ups**\n]\n")
+				So(diagram, ShouldContainSubstring, "appguid --> upsguid\n")
+				So(diagram, ShouldNotContainSubstring, "secret")
+			})
+
+			Convey("Then the network policies are shown with their peer apps", func() {
+				So(diagram, ShouldContainSubstring, "component backendappguid <<app>> [\n**backend-app**\n")
+				So(diagram, ShouldContainSubstring, "appguid ..> backendappguid : tcp 8080\n")
+				So(diagram, ShouldContainSubstring, "[**foreign-app-guid**] <<app>> as foreignappguid\n")
+				So(diagram, ShouldContainSubstring, "foreignappguid ..> appguid : udp 9000-9010\n")
+			})
 
 		})
 
 	})
 
+	Convey("Given a user who may not see the space of the app", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/spaces"] = `{"pagination": {"total_results": 0}, "resources": []}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			_, err := singleAppDiagramService.GetRawDiagram("app-guid")
+
+			Convey("Then an error names the missing space", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, "space with id space-guid of app my-app not found")
+			})
+
+		})
+
+	})
+
+	Convey("Given a user who may not read network policies", t, func() {
+
+		foundation := testingFoundationHandler(testingFoundationResponses())
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/networking/v1/external/policies" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error": "token missing allowed scopes: [network.admin network.write]"}`))
+				return
+			}
+			foundation.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+
+		config := testingConfig(server)
+		singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			diagram, err := singleAppDiagramService.GetRawDiagram("app-guid")
+
+			Convey("Then the diagram is rendered with a warning instead of the policies", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Single App Diagram - my-app\n")
+				So(diagram, ShouldContainSubstring, "rectangle network_policies_warning <<warning>> #Pink [\n**Network policies not shown**\n"+
+					"<color:red>access denied by the policy server, network.admin or network.write scope required</color>\n]\n")
+				So(diagram, ShouldNotContainSubstring, "..>")
+			})
+
+		})
+
+	})
+
+	Convey("Given app with labels and a config selecting labels for the diagram", t, func() {
+
+		server := testingFoundation(testingFoundationResponses())
+		defer server.Close()
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			Convey("Then the app shows the selected labels and annotations and the label stereotype", func() {
+				config := testingConfig(server)
+				config.DiagramOptions.LabelKeys = []string{"team", "unknown"}
+				config.DiagramOptions.AnnotationKeys = []string{"contact"}
+				config.DiagramOptions.StereotypeLabel = "tier"
+				config.DiagramOptions.ColorLabel = "tier"
+				config.DiagramOptions.LabelColors = map[string]string{"frontend": "LightBlue"}
+				singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+				diagram, err := singleAppDiagramService.GetRawDiagram("app-guid")
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "component \"**my-app**\\nState: STARTED\\n")
+				So(diagram, ShouldContainSubstring, "\\nteam: payments\\ncontact: payments@example.org\" as appguid <<app>> <<frontend>> #LightBlue {\n")
+				So(diagram, ShouldNotContainSubstring, "unknown")
+			})
+
+		})
+
+	})
+
+	Convey("Given app with processes and a config selecting instance details", t, func() {
+
+		server := testingFoundation(testingFoundationResponses())
+		defer server.Close()
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			Convey("Then the instances are nested inside their processes", func() {
+				config := testingConfig(server)
+				config.RecentTasks = 1
+				config.DiagramOptions.InstanceDetails = true
+				singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+				diagram, err := singleAppDiagramService.GetRawDiagram("app-guid")
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "component \"**web**\\nInstances: 1/2 running\\nMemory: 1024 MB\\nDisk: 1024 MB\\nHealth check: http /health\" as webprocessguid <<process>> {\n"+
+					"component webprocessguid_0 <<instance>> [\n**#0**\nState: RUNNING\nUptime: 1h2m3s\nCPU: 12.5%\nMemory: 300 MB / 1024 MB\n]\n"+
+					"component webprocessguid_1 <<instance>> [\n**#1**\nState: CRASHED\n]\n"+
+					"}\n")
+				So(diagram, ShouldContainSubstring, "appguid --> failedtaskguid : task\n")
+				So(diagram, ShouldNotContainSubstring, "appguid --> taskguid : task\n")
+			})
+
+		})
+
+	})
+
+}
+
+func TestSingleAppDiagramLifecycles(t *testing.T) {
+
+	Convey("Given a docker app", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/apps/docker-app-guid"] = `{"guid": "docker-app-guid", "name": "docker-app", "state": "STARTED",
+			"lifecycle": {"type": "docker", "data": {}}, "relationships": {"space": {"data": {"guid": "space-guid"}}}}`
+		responses["/v3/apps/docker-app-guid/droplets/current"] = `{"guid": "docker-droplet-guid", "state": "STAGED",
+			"image": "registry.example.org:5000/team/app@sha256:abc"}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			Convey("Then the docker image is shown as dependency", func() {
+				config := testingConfig(server)
+				singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+				diagram, err := singleAppDiagramService.GetRawDiagram("docker-app-guid")
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "component image_registry_example_org_5000_team_app_sha256_abc <<docker image>> [\n**team/app**\nRegistry: registry.example.org:5000\nDigest: sha256:abc\n]\n")
+				So(diagram, ShouldContainSubstring, "dockerappguid --> image_registry_example_org_5000_team_app_sha256_abc\n")
+				So(diagram, ShouldNotContainSubstring, "<<stack>>")
+			})
+
+		})
+
+	})
+
+	Convey("Given an app using cloud native buildpacks", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/apps/cnb-app-guid"] = `{"guid": "cnb-app-guid", "name": "cnb-app", "state": "STARTED",
+			"lifecycle": {"type": "cnb", "data": {"buildpacks": ["docker://gcr.io/paketo-buildpacks/java:10.0", "paketo-nodejs"], "stack": "cflinuxfs4"}},
+			"relationships": {"space": {"data": {"guid": "space-guid"}}}}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			Convey("Then the buildpacks and the stack are shown as dependencies", func() {
+				config := testingConfig(server)
+				singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+				diagram, err := singleAppDiagramService.GetRawDiagram("cnb-app-guid")
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "component cnb_docker_gcr_io_paketo_buildpacks_java_10_0 <<cnb>> [\n**paketo-buildpacks/java**\nRegistry: gcr.io\nTag: 10.0\n]\n")
+				So(diagram, ShouldContainSubstring, "cnbappguid --> cnb_docker_gcr_io_paketo_buildpacks_java_10_0\n")
+				So(diagram, ShouldContainSubstring, "[**paketo-nodejs**] <<cnb>> as cnb_paketo_nodejs\n")
+				So(diagram, ShouldContainSubstring, "cnbappguid --> cnb_paketo_nodejs\n")
+				So(diagram, ShouldContainSubstring, "cnbappguid --> cflinuxfs4\n")
+			})
+
+		})
+
+	})
+
+	Convey("Given apps in a space of an organization", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/apps?names=my-app&space_guids=space-guid"] = `{"pagination": {"total_results": 1}, "resources": [` + testingApp + `]}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+		Convey("When the SingleAppDiagram is rendered by the names of org, space and app", func() {
+
+			diagram, err := singleAppDiagramService.GetRawDiagramByNames("my-org", "my-space", "my-app")
+
+			Convey("Then the diagram of the app is returned", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Single App Diagram - my-app\n")
+			})
+
+		})
+
+		Convey("When the SingleAppDiagram is rendered by an app name matching several apps", func() {
+
+			_, err := singleAppDiagramService.GetRawDiagramByNames("", "", "backend-app")
+
+			Convey("Then an error messages lists the matching apps", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, `app "backend-app" is ambiguous, matches app-guid, backend-app-guid`)
+			})
+
+		})
+
+	})
+
+	Convey("Given a config with another renderer", t, func() {
+
+		server := testingFoundation(testingFoundationResponses())
+		defer server.Close()
+
+		renderer := &testingRenderer{}
+		config := testingConfig(server)
+		config.Renderer = renderer
+		singleAppDiagramService, _ := NewSingleAppDiagramService(&config)
+
+		Convey("When the SingleAppDiagram is rendered", func() {
+
+			source, err := singleAppDiagramService.GetRawDiagram("app-guid")
+
+			Convey("Then the renderer gets the diagram model of the app", func() {
+				So(err, ShouldEqual, nil)
+				So(source, ShouldEqual, "Single App Diagram - my-app")
+				So(renderer.diagram.Node("app-guid").Type, ShouldEqual, diagram.NodeApp)
+				So(renderer.diagram.Node("web-process-guid").Type, ShouldEqual, diagram.NodeProcess)
+				So(renderer.diagram.HasEdge(diagram.Edge{From: "app-guid", To: "backend-app-guid", Type: diagram.EdgeNetworkPolicy, Label: "tcp 8080"}), ShouldEqual, true)
+			})
+
+		})
+
+	})
+
+}
+
+// testingRenderer keeps the rendered diagram and returns its title.
+type testingRenderer struct {
+	diagram *diagram.Diagram
+}
+
+func (r *testingRenderer) Render(d *diagram.Diagram) string {
+	r.diagram = d
+	return d.Title
 }
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry"
+	"github.com/nrekretep/cloudpaint/adapter/cloudfoundry/v3"
+	"sort"
+)
+
+// SpaceDiagramService renders all apps of a space with their routes,
+// services, network policies and runtime dependencies. Only the resources
+// of the space are loaded, not the whole foundation.
+type SpaceDiagramService struct {
+	config *Config
+}
+
+// NewSpaceDiagramService -
+func NewSpaceDiagramService(c *Config) (*SpaceDiagramService, error) {
+
+	if c == nil {
+		return nil, errors.New("a non empty config must be provided to a diagram service")
+	}
+
+	diagramService := &SpaceDiagramService{config: c}
+
+	return diagramService, nil
+}
+
+// GetRawDiagram returns the plantuml source of the diagram for the space.
+func (s *SpaceDiagramService) GetRawDiagram(spaceID string) (string, error) {
+	return s.GetRawDiagramContext(context.Background(), spaceID)
+}
+
+// GetRawDiagramContext is like GetRawDiagram but uses the given context.
+func (s *SpaceDiagramService) GetRawDiagramContext(ctx context.Context, spaceID string) (string, error) {
+
+	if spaceID == "" {
+		return "", errors.New("a valid id for the space must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	spaces, err := cloudController.QueryV3SpacesContext(ctx, (&v3.ListQuery{}).Filter("guids", spaceID))
+	if err != nil {
+		return "", err
+	}
+	if len(spaces) == 0 {
+		return "", errors.New("space with id " + spaceID + " not found")
+	}
+
+	return s.render(ctx, cloudController, spaces[0])
+}
+
+// GetRawDiagramByNames returns the plantuml source of the diagram for the
+// space with the name in the organization with the name. An empty
+// organization name means the org targeted by the cf CLI or all orgs.
+func (s *SpaceDiagramService) GetRawDiagramByNames(orgName string, spaceName string) (string, error) {
+	return s.GetRawDiagramByNamesContext(context.Background(), orgName, spaceName)
+}
+
+// GetRawDiagramByNamesContext is like GetRawDiagramByNames but uses the
+// given context.
+func (s *SpaceDiagramService) GetRawDiagramByNamesContext(ctx context.Context, orgName string, spaceName string) (string, error) {
+
+	if spaceName == "" {
+		return "", errors.New("the name of the space must be provided")
+	}
+
+	cloudController, err := s.config.newCloudController(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	space, err := cloudController.ResolveSpaceContext(ctx, orgName, spaceName)
+	if err != nil {
+		return "", err
+	}
+
+	return s.render(ctx, cloudController, space)
+}
+
+// render loads the resources of the space and renders the diagram.
+func (s *SpaceDiagramService) render(ctx context.Context, cloudController *cloudfoundry.CloudController, space *v3.Space) (string, error) {
+
+	orgs, err := cloudController.QueryV3OrganizationsContext(ctx, (&v3.ListQuery{}).Filter("guids", space.OrganizationGUID()))
+	if err != nil {
+		return "", err
+	}
+	if len(orgs) == 0 {
+		return "", errors.New("organization with id " + space.OrganizationGUID() + " not found")
+	}
+
+	apps, err := cloudController.QueryV3AppsContext(ctx, (&v3.ListQuery{PerPage: v3.MaxPerPage}).Filter("space_guids", space.GUID))
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	policyWarning, err := loadAppDependencies(ctx, cloudController, apps)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = cloudController.QueryV3RoutesContext(ctx, (&v3.ListQuery{PerPage: v3.MaxPerPage}).Filter("space_guids", space.GUID))
+	if err != nil {
+		return "", err
+	}
+
+	_, err = cloudController.GetV3SpaceServiceInstancesContext(ctx, space.GUID)
+	if err != nil {
+		return "", err
+	}
+
+	d := s.config.newBuilder(cloudController).SpaceDiagram(orgs[0], space, apps)
+	addNetworkPolicyWarning(d, policyWarning)
+
+	return s.config.render(d), nil
+}
+
+// loadAppDependencies loads the service bindings and network policies of
+// the apps and the lineage of docker apps, which contains their image. The
+// warning of loadNetworkPolicies is returned.
+func loadAppDependencies(ctx context.Context, cloudController *cloudfoundry.CloudController, apps []*v3.App) (string, error) {
+
+	if len(apps) == 0 {
+		return "", nil
+	}
+
+	appGUIDs := make([]string, 0, len(apps))
+	for _, app := range apps {
+		appGUIDs = append(appGUIDs, app.GUID)
+
+		if app.Lifecycle.GetType() == v3.LifecycleDocker {
+			_, err := cloudController.GetV3AppLineageContext(ctx, app.GUID)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	_, err := cloudController.GetV3AppsServiceBindingsContext(ctx, appGUIDs...)
+	if err != nil {
+		return "", err
+	}
+
+	return loadNetworkPolicies(ctx, cloudController, appGUIDs...)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestSpaceDiagram(t *testing.T) {
+
+	Convey("Given the config for the diagram does not exist", t, func() {
+
+		Convey("When the SpaceDiagram Service is created", func() {
+
+			Convey("Then an error messages indicates the missing config", func() {
+				diagramService, err := NewSpaceDiagramService(nil)
+
+				So(diagramService, ShouldEqual, nil)
+				So(err.Error(), ShouldEqual, "a non empty config must be provided to a diagram service")
+			})
+
+		})
+
+	})
+
+	Convey("Given a space with apps, routes, services and network policies", t, func() {
+
+		responses := testingFoundationResponses()
+		responses["/v3/routes"] = responses["/v3/apps/app-guid/routes"]
+		responses["/v3/service_instances"] = `{"pagination": {"total_results": 3}, "resources": [
+			{"guid": "db-guid", "name": "my-db", "type": "managed",
+				"relationships": {"space": {"data": {"guid": "space-guid"}}, "service_plan": {"data": {"guid": "plan-guid"}}}},
+			{"guid": "ups-guid", "name": "my-ups", "type": "user-provided",
+				"relationships": {"space": {"data": {"guid": "space-guid"}}}},
+			{"guid": "unbound-guid", "name": "my-cache", "type": "user-provided",
+				"relationships": {"space": {"data": {"guid": "space-guid"}}}}
+		]}`
+		responses["/v3/organizations?names=unknown-org"] = `{"pagination": {"total_results": 0}, "resources": []}`
+		server := testingFoundation(responses)
+		defer server.Close()
+
+		config := testingConfig(server)
+		diagramService, _ := NewSpaceDiagramService(&config)
+
+		Convey("When the SpaceDiagram is rendered", func() {
+
+			diagram, err := diagramService.GetRawDiagram("space-guid")
+
+			Convey("Then the diagram shows all apps of the space with their dependencies", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Space Diagram - my-org / my-space\n")
+				So(diagram, ShouldContainSubstring, "orgguid --> spaceguid\n")
+				So(diagram, ShouldContainSubstring, "spaceguid --> appguid\n")
+				So(diagram, ShouldContainSubstring, "spaceguid --> backendappguid\n")
+				So(diagram, ShouldContainSubstring, "routeguid --> appguid : 8080 http1 (web)\n")
+				So(diagram, ShouldContainSubstring, "appguid --> dbguid : db\n")
+				So(diagram, ShouldContainSubstring, "appguid ..> backendappguid : tcp 8080\n")
+				So(diagram, ShouldContainSubstring, "appguid --> java_buildpack\n")
+				So(diagram, ShouldContainSubstring, "component unboundguid <<user-provided service>> [\n**my-cache**\n]\n")
+			})
+
+			Convey("Then every component is written once", func() {
+				So(strings.Count(diagram, "component routeguid "), ShouldEqual, 1)
+				So(strings.Count(diagram, "component backendappguid "), ShouldEqual, 1)
+				So(strings.Count(diagram, "component dbguid "), ShouldEqual, 1)
+			})
+
+		})
+
+		Convey("When the SpaceDiagram is rendered by the names of org and space", func() {
+
+			diagram, err := diagramService.GetRawDiagramByNames("my-org", "my-space")
+
+			Convey("Then the diagram of the space is returned", func() {
+				So(err, ShouldEqual, nil)
+				So(diagram, ShouldContainSubstring, "title Space Diagram - my-org / my-space\n")
+			})
+
+		})
+
+		Convey("When the SpaceDiagram is rendered for an unknown organization", func() {
+
+			_, err := diagramService.GetRawDiagramByNames("unknown-org", "my-space")
+
+			Convey("Then an error messages indicates the wrong organization", func() {
+				So(err, ShouldNotEqual, nil)
+				So(err.Error(), ShouldEqual, `organization "unknown-org" not found`)
+			})
+
+		})
+
+	})
+
+}